@@ -0,0 +1,146 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"net"
+	"os"
+)
+
+var (
+	// ErrNoMachineID is returned when no machine ID can be derived and the random fallback is disabled
+	ErrNoMachineID = errors.New("no machine ID source available")
+)
+
+// MachineIDSource is the source a machine ID was derived from by NewGeneratorAuto
+type MachineIDSource string
+
+const (
+	// MachineIDFromHostname means the machine ID is a hash of the hostname
+	MachineIDFromHostname MachineIDSource = "hostname"
+	// MachineIDFromMAC means the machine ID is a hash of the first hardware address of a network interface
+	MachineIDFromMAC MachineIDSource = "mac"
+	// MachineIDFromRandom means the machine ID is random
+	MachineIDFromRandom MachineIDSource = "random"
+	// MachineIDFromProvider means the machine ID is from a provider of the options, such as WithMachineIDProvider or
+	// WithMachineIDFromHost
+	MachineIDFromProvider MachineIDSource = "provider"
+)
+
+// hostname and hardwareAddr are the sources of NewGeneratorAuto, they are variables to replace them in tests
+var (
+	hostname     = os.Hostname
+	hardwareAddr = firstHardwareAddr
+)
+
+// WithoutRandomMachineID disables the random fallback of NewGeneratorAuto
+func WithoutRandomMachineID() Option {
+	return func(generator *Generator) {
+		generator.noRandomID = true
+	}
+}
+
+// NewGeneratorAuto creates a new snowflake ID generator with a machine ID derived from the machine, for development
+// and single node setups
+// The machine ID is the FNV-1a hash of the first available source, reduced to the machine ID bits:
+//  1. the hostname
+//  2. the hardware address of the first network interface that has one
+//  3. a random number, unless WithoutRandomMachineID is given
+//
+// Hashes of different hosts can collide, so assign machine IDs explicitly when multiple nodes generate IDs
+// A machine ID provider in opts, such as WithMachineIDProvider or WithMachineIDFromHost, replaces the derivation
+// Returns the source of the machine ID, so the caller can log it, which is MachineIDFromProvider when a provider of
+// opts replaced the derivation
+// Returns ErrNoMachineID if no source is available, or an error if the generator cannot be created
+func NewGeneratorAuto(opts ...Option) (*Generator, MachineIDSource, error) {
+	source := MachineIDFromProvider
+	derive := func(generator *Generator) {
+		generator.provider = func(context.Context) (uint64, error) {
+			machineID, derived, err := generator.autoMachineID()
+			source = derived
+			return machineID, err
+		}
+	}
+	g, err := NewGenerator(0, append([]Option{derive}, opts...)...)
+	if err != nil {
+		return nil, "", err
+	}
+	return g, source, nil
+}
+
+// autoMachineID derives the machine ID of NewGeneratorAuto for the machine ID bits of the generator
+// Returns the error of the layout if it is invalid and ErrNoMachineID if no source is available
+func (g *Generator) autoMachineID() (uint64, MachineIDSource, error) {
+	if err := g.layout.validate(); err != nil {
+		return 0, "", err
+	}
+	mask := g.layout.machineIDMask()
+	if name, err := hostname(); err == nil && name != "" {
+		return hash(name) & mask, MachineIDFromHostname, nil
+	}
+	if addr := hardwareAddr(); len(addr) > 0 {
+		return hash(string(addr)) & mask, MachineIDFromMAC, nil
+	}
+	if g.noRandomID {
+		return 0, "", ErrNoMachineID
+	}
+	random, err := randomNonce(mask)
+	if err != nil {
+		return 0, "", err
+	}
+	return random, MachineIDFromRandom, nil
+}
+
+// MachineIDFromHost derives a stable machine ID with the given number of bits from the identity of the host
+// The machine ID is the FNV-1a hash of the hardware address of the first non-loopback network interface, or of the
+// hostname when there is none, reduced to the bits. Unlike NewGeneratorAuto it prefers the hardware address, which is
+// usually unique across a fleet while hostnames are often reused
+// Hashes of different hosts can collide: with b bits, n hosts share a machine ID with a probability of about
+// n²/2^(b+1), which is already likely for a few dozen hosts with 10 bits, see CollisionProbability
+// Returns ErrNoMachineID if the host has neither a hardware address nor a hostname, or an error if the bits are invalid
+func MachineIDFromHost(bits uint64) (uint64, error) {
+	if err := (Layout{MachineIDBits: bits}).validate(); err != nil {
+		return 0, err
+	}
+	mask := uint64(1)<<bits - 1
+	if addr := hardwareAddr(); len(addr) > 0 {
+		return hash(string(addr)) & mask, nil
+	}
+	if name, err := hostname(); err == nil && name != "" {
+		return hash(name) & mask, nil
+	}
+	return 0, ErrNoMachineID
+}
+
+// WithMachineIDFromHost derives the machine ID with MachineIDFromHost for the machine ID bits of the generator, it
+// replaces the machine ID that is passed to the constructor
+// NewGenerator returns the error of MachineIDFromHost
+func WithMachineIDFromHost() Option {
+	return func(generator *Generator) {
+		generator.provider = func(context.Context) (uint64, error) {
+			return MachineIDFromHost(generator.layout.MachineIDBits)
+		}
+	}
+}
+
+// hash returns the FNV-1a hash of s
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// firstHardwareAddr returns the first hardware address of a non-loopback network interface, or nil if there is none
+func firstHardwareAddr() net.HardwareAddr {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	for _, i := range interfaces {
+		if len(i.HardwareAddr) > 0 && i.Flags&net.FlagLoopback == 0 {
+			return i.HardwareAddr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// TestNewGeneratorAuto tests the fallback chain of NewGeneratorAuto
+func TestNewGeneratorAuto(t *testing.T) {
+	defer func(h func() (string, error), a func() net.HardwareAddr) {
+		hostname, hardwareAddr = h, a
+	}(hostname, hardwareAddr)
+	noHostname := func() (string, error) {
+		return "", errors.New("no hostname")
+	}
+	mac := net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}
+
+	tests := []struct {
+		name          string
+		hostname      func() (string, error)
+		hardwareAddr  func() net.HardwareAddr
+		opts          []Option
+		wantSource    MachineIDSource
+		wantMachineID uint64
+		wantErr       error
+	}{
+		{
+			name:          "hostname",
+			hostname:      func() (string, error) { return "node-1", nil },
+			hardwareAddr:  func() net.HardwareAddr { return mac },
+			opts:          []Option{WithMachineIDBits(16)},
+			wantSource:    MachineIDFromHostname,
+			wantMachineID: hash("node-1") & (1<<16 - 1),
+		},
+		{
+			name:          "mac",
+			hostname:      noHostname,
+			hardwareAddr:  func() net.HardwareAddr { return mac },
+			wantSource:    MachineIDFromMAC,
+			wantMachineID: hash(string(mac)) & (1<<10 - 1),
+		},
+		{
+			name:         "random",
+			hostname:     noHostname,
+			hardwareAddr: func() net.HardwareAddr { return nil },
+			wantSource:   MachineIDFromRandom,
+		},
+		{
+			name:         "without random",
+			hostname:     noHostname,
+			hardwareAddr: func() net.HardwareAddr { return nil },
+			opts:         []Option{WithoutRandomMachineID()},
+			wantErr:      ErrNoMachineID,
+		},
+		{
+			name:         "provider",
+			hostname:     func() (string, error) { return "node-1", nil },
+			hardwareAddr: func() net.HardwareAddr { return mac },
+			opts: []Option{WithMachineIDProvider(func(context.Context) (uint64, error) {
+				return 42, nil
+			})},
+			wantSource:    MachineIDFromProvider,
+			wantMachineID: 42,
+		},
+		{
+			name:          "from host",
+			hostname:      func() (string, error) { return "node-1", nil },
+			hardwareAddr:  func() net.HardwareAddr { return mac },
+			opts:          []Option{WithMachineIDFromHost()},
+			wantSource:    MachineIDFromProvider,
+			wantMachineID: hash(string(mac)) & (1<<10 - 1),
+		},
+		{
+			name:         "invalid layout",
+			hostname:     func() (string, error) { return "node-1", nil },
+			hardwareAddr: func() net.HardwareAddr { return mac },
+			opts:         []Option{WithMachineIDBits(0)},
+			wantErr:      ErrMachineBitsTooSmall,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostname, hardwareAddr = tt.hostname, tt.hardwareAddr
+			g, source, err := NewGeneratorAuto(tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if source != tt.wantSource {
+				t.Errorf("expected %v, got %v", tt.wantSource, source)
+			}
+			if tt.wantSource != MachineIDFromRandom && g.MachineID() != tt.wantMachineID {
+				t.Errorf("expected %v, got %v", tt.wantMachineID, g.MachineID())
+			}
+		})
+	}
+}
+
+// TestMachineIDFromHost tests that MachineIDFromHost prefers the hardware address and falls back to the hostname
+func TestMachineIDFromHost(t *testing.T) {
+	defer func(h func() (string, error), a func() net.HardwareAddr) {
+		hostname, hardwareAddr = h, a
+	}(hostname, hardwareAddr)
+	noHostname := func() (string, error) {
+		return "", errors.New("no hostname")
+	}
+	mac := net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}
+
+	tests := []struct {
+		name          string
+		hostname      func() (string, error)
+		hardwareAddr  func() net.HardwareAddr
+		bits          uint64
+		wantMachineID uint64
+		wantErr       error
+	}{
+		{
+			name:          "mac",
+			hostname:      func() (string, error) { return "node-1", nil },
+			hardwareAddr:  func() net.HardwareAddr { return mac },
+			bits:          10,
+			wantMachineID: hash(string(mac)) & (1<<10 - 1),
+		},
+		{
+			name:          "hostname",
+			hostname:      func() (string, error) { return "node-1", nil },
+			hardwareAddr:  func() net.HardwareAddr { return nil },
+			bits:          16,
+			wantMachineID: hash("node-1") & (1<<16 - 1),
+		},
+		{
+			name:         "none",
+			hostname:     noHostname,
+			hardwareAddr: func() net.HardwareAddr { return nil },
+			bits:         10,
+			wantErr:      ErrNoMachineID,
+		},
+		{
+			name:         "invalid bits",
+			hostname:     noHostname,
+			hardwareAddr: func() net.HardwareAddr { return mac },
+			bits:         22,
+			wantErr:      ErrMachineBitsTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostname, hardwareAddr = tt.hostname, tt.hardwareAddr
+			machineID, err := MachineIDFromHost(tt.bits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if machineID != tt.wantMachineID {
+				t.Errorf("expected %v, got %v", tt.wantMachineID, machineID)
+			}
+		})
+	}
+}
+
+// TestWithMachineIDFromHost tests that the option derives the machine ID for the machine ID bits of the generator
+func TestWithMachineIDFromHost(t *testing.T) {
+	defer func(h func() (string, error), a func() net.HardwareAddr) {
+		hostname, hardwareAddr = h, a
+	}(hostname, hardwareAddr)
+	hostname = func() (string, error) {
+		return "", errors.New("no hostname")
+	}
+	mac := net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}
+	hardwareAddr = func() net.HardwareAddr {
+		return mac
+	}
+
+	// The option is given before WithMachineIDBits, the machine ID is derived after all options are applied
+	g, err := NewGenerator(0, WithMachineIDFromHost(), WithMachineIDBits(16))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if want := hash(string(mac)) & (1<<16 - 1); g.MachineID() != want {
+		t.Errorf("expected %v, got %v", want, g.MachineID())
+	}
+
+	hardwareAddr = func() net.HardwareAddr {
+		return nil
+	}
+	if _, err := NewGenerator(0, WithMachineIDFromHost()); !errors.Is(err, ErrNoMachineID) {
+		t.Errorf("expected %v, got %v", ErrNoMachineID, err)
+	}
+}
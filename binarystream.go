@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// BinaryStreamDecoder reads snowflake IDs framed as 8 big-endian bytes without a delimiter, one ID at a time
+// Use it like a bufio.Scanner, call Next until it returns false and check Err afterwards
+type BinaryStreamDecoder struct {
+	r   io.Reader
+	buf [8]byte
+	id  ID
+	err error
+}
+
+// DecodeBinaryStream returns a decoder for IDs framed as 8 big-endian bytes without a delimiter, as written by
+// EncodeBinaryStream
+// Only one frame is held in memory, so the stream can be arbitrarily large
+func DecodeBinaryStream(r io.Reader) *BinaryStreamDecoder {
+	return &BinaryStreamDecoder{r: r}
+}
+
+// Next reads the next ID, it returns false at the end of the stream or on an error
+func (d *BinaryStreamDecoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+	if _, err := io.ReadFull(d.r, d.buf[:]); err != nil {
+		d.err = err
+		return false
+	}
+	d.id = ID(binary.BigEndian.Uint64(d.buf[:]))
+	return true
+}
+
+// ID returns the ID read by the last call to Next
+func (d *BinaryStreamDecoder) ID() ID {
+	return d.id
+}
+
+// Err returns the error that stopped Next, it returns nil when the stream ended after a complete frame and
+// io.ErrUnexpectedEOF when the final frame is partial
+func (d *BinaryStreamDecoder) Err() error {
+	if d.err == io.EOF {
+		return nil
+	}
+	return d.err
+}
+
+// EncodeBinaryStream writes the IDs framed as 8 big-endian bytes without a delimiter, as read by DecodeBinaryStream
+// It can be called repeatedly on the same writer to append to the stream
+func EncodeBinaryStream(w io.Writer, ids ...ID) error {
+	var buf [8]byte
+	for _, id := range ids {
+		binary.BigEndian.PutUint64(buf[:], uint64(id))
+		if _, err := w.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
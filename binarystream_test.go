@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+)
+
+// TestDecodeBinaryStream tests that IDs written by EncodeBinaryStream are read back in order
+func TestDecodeBinaryStream(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []ID
+	}{
+		{name: "empty"},
+		{name: "single", ids: []ID{0x0102030405060708}},
+		{name: "many", ids: []ID{0, 1, 0x123456789ABCDEF0, 0xFFFFFFFFFFFFFFFF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeBinaryStream(&buf, tt.ids...); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if buf.Len() != 8*len(tt.ids) {
+				t.Errorf("expected %d bytes, got %d", 8*len(tt.ids), buf.Len())
+				return
+			}
+			// One byte reads make sure frames are assembled across reads
+			decoder := DecodeBinaryStream(iotest.OneByteReader(&buf))
+			var ids []ID
+			for decoder.Next() {
+				ids = append(ids, decoder.ID())
+			}
+			if err := decoder.Err(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("expected %v, got %v", tt.ids, ids)
+			}
+		})
+	}
+}
+
+// TestDecodeBinaryStream_Framing tests that the frames are big-endian
+func TestDecodeBinaryStream_Framing(t *testing.T) {
+	decoder := DecodeBinaryStream(bytes.NewReader([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	if !decoder.Next() {
+		t.Errorf("expected an ID, got %v", decoder.Err())
+		return
+	}
+	if decoder.ID() != 0x0102030405060708 {
+		t.Errorf("expected %v, got %v", ID(0x0102030405060708), decoder.ID())
+	}
+}
+
+// TestDecodeBinaryStream_PartialFrame tests that a partial final frame is reported as io.ErrUnexpectedEOF
+func TestDecodeBinaryStream_PartialFrame(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeBinaryStream(&buf, 1, 2); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	buf.Write([]byte{1, 2, 3})
+	decoder := DecodeBinaryStream(&buf)
+	count := 0
+	for decoder.Next() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 IDs, got %d", count)
+	}
+	if !errors.Is(decoder.Err(), io.ErrUnexpectedEOF) {
+		t.Errorf("expected %v, got %v", io.ErrUnexpectedEOF, decoder.Err())
+	}
+	if decoder.Next() {
+		t.Errorf("expected no more IDs after an error")
+	}
+}
+
+// TestEncodeBinaryStream_Error tests that a write error is returned
+func TestEncodeBinaryStream_Error(t *testing.T) {
+	expected := errors.New("write failed")
+	w := &failingWriter{err: expected}
+	if err := EncodeBinaryStream(w, 1); !errors.Is(err, expected) {
+		t.Errorf("expected %v, got %v", expected, err)
+	}
+}
+
+// failingWriter is an io.Writer that always fails
+type failingWriter struct {
+	err error
+}
+
+// Write returns the error of the writer
+func (w *failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
@@ -0,0 +1,37 @@
+package snowflake
+
+import "errors"
+
+var (
+	// ErrBlockNotContiguous is returned by ReserveBlock when the sequence is not in the least significant bits
+	ErrBlockNotContiguous = errors.New("IDs of a block are not contiguous")
+)
+
+// ReserveBlock reserves up to n consecutive sequence numbers of the current millisecond with a single reservation,
+// for a downstream allocator that assigns the IDs start, start+1, ... start+count-1 without calling the generator
+// The block never crosses a millisecond: when the current millisecond cannot hold n more IDs only the IDs left in it
+// are reserved, and when it is exhausted the block is taken from the next millisecond like NextID, which requires
+// drift. The sequence is in the least significant bits, so start+i only increments the sequence and never carries
+// into the nonce, shard or machine ID bits. NextID and the other methods of the generator never return a reserved ID
+// The IDs of the block are not recorded in the history and not reported to the observer
+// Returns 0, 0 and no error if n is zero or less, ErrBlockNotContiguous with another field order than
+// OrderTimestampMachineSequence, because the sequence is then not in the least significant bits, and the errors of
+// NextID
+func (g *Generator) ReserveBlock(n int) (start ID, count int, err error) {
+	g.lastCallBlocked.Store(false)
+	if n <= 0 {
+		return 0, 0, nil
+	}
+	if g.layout.Order != OrderTimestampMachineSequence {
+		return 0, 0, ErrBlockNotContiguous
+	}
+	_, state, err := g.reserveLinked(0, uint64(n), *g.timeFunc.Load())
+	if err != nil {
+		return 0, 0, err
+	}
+	reserved := g.sequenceMask - state&g.sequenceMask + 1
+	if reserved > uint64(n) {
+		reserved = uint64(n)
+	}
+	return g.compose(state, g.machineID.Load(), 0), int(reserved), nil
+}
@@ -0,0 +1,93 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestGenerator_ReserveBlock tests that a block holds contiguous IDs within a millisecond that NextID does not return
+func TestGenerator_ReserveBlock(t *testing.T) {
+	// With 20 machine ID bits there are four sequence numbers per millisecond
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(20),
+		WithDriftNoWait(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	first, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// Three IDs are left in the millisecond
+	start, count, err := generator.ReserveBlock(5)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if start != first+1 || count != 3 {
+		t.Errorf("expected %v and 3, got %v and %v", uint64(first+1), uint64(start), count)
+		return
+	}
+	for i := 0; i < count; i++ {
+		got := generator.DecodeID(start + ID(i))
+		if got.Timestamp != 367597485448 || got.MachineID != 378 || got.Sequence != uint64(i+1) {
+			t.Errorf("expected sequence %v of machine ID 378, got %v", i+1, got)
+			return
+		}
+	}
+
+	// The next millisecond is reserved with drift, leaving room for the ID of NextID
+	start, count, err = generator.ReserveBlock(3)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.DecodeID(start); got.Timestamp != 367597485449 || got.Sequence != 0 || count != 3 {
+		t.Errorf("expected 3 IDs from sequence 0 of the next millisecond, got %v and %v", got, count)
+		return
+	}
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if id != start+ID(count) {
+		t.Errorf("expected %v, got %v", uint64(start+ID(count)), uint64(id))
+		return
+	}
+
+	if start, count, err = generator.ReserveBlock(0); start != 0 || count != 0 || err != nil {
+		t.Errorf("expected 0, 0 and no error, got %v, %v and %v", uint64(start), count, err)
+	}
+}
+
+// TestGenerator_ReserveBlock_Errors tests the errors of ReserveBlock
+func TestGenerator_ReserveBlock_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want error
+	}{
+		{name: "spread layout", opts: []Option{WithSpreadLayout()}, want: ErrBlockNotContiguous},
+		{name: "sequence strategy", opts: []Option{WithSequenceStrategy(NewIncrementSequenceStrategy(12))},
+			want: ErrSequenceStrategyBatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(378, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if _, _, err = generator.ReserveBlock(10); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
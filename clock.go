@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of a generator, it replaces both the time function and the sleep function
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// Sleep pauses for the duration
+	Sleep(d time.Duration)
+}
+
+// WithClockInterface sets the clock of the generator, which is used to read the time and to sleep until the next
+// millisecond, or time unit, instead of the time function and the sleep function
+func WithClockInterface(c Clock) Option {
+	return func(generator *Generator) {
+		generator.SetTimeFunc(func() uint64 {
+			return uint64(generator.ticks(c.Now()))
+		})
+		generator.sleepFunc = func() {
+			c.Sleep(generator.unit - time.Duration(c.Now().UnixNano())%generator.unit)
+		}
+	}
+}
+
+// WithTimeFunc sets the time function of the generator, which returns the current time in milliseconds, or in the
+// time unit of WithTimeUnit, since the Unix epoch, for example from a PTP clock or a fake clock in tests
+// It is equivalent to SetTimeFunc before the first ID, the default reads time.Now and a nil function keeps it
+func WithTimeFunc(timeFunc TimeFunc) Option {
+	return func(generator *Generator) {
+		if timeFunc != nil {
+			generator.SetTimeFunc(timeFunc)
+		}
+	}
+}
+
+// WithSleepFunc sets the function the generator calls to wait for the time function to move on when the sequence is
+// exhausted, see WithWaitStrategy for the built-in strategies
+// The default sleeps until the next millisecond, or time unit, of time.Now and a nil function keeps it
+func WithSleepFunc(sleepFunc func()) Option {
+	return func(generator *Generator) {
+		if sleepFunc != nil {
+			generator.sleepFunc = sleepFunc
+		}
+	}
+}
+
+// ManualClock is a Clock for tests that only advances when it is told to
+// Sleep advances the clock by the duration instead of pausing, so code that waits for the clock never blocks
+// A ManualClock is safe for concurrent use
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock creates a manual clock that starts at the given time
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now returns the current time of the clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the clock by the duration
+func (c *ManualClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// Advance advances the clock by the duration
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set sets the clock to the given time, which may be before the current time to simulate a clock moving backwards
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
@@ -0,0 +1,133 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestWithClockInterface tests that the generator reads the time from the clock and sleeps with it
+func TestWithClockInterface(t *testing.T) {
+	clock := NewManualClock(time.UnixMilli(367597485448).Add(300 * time.Microsecond))
+	// With 21 machine ID bits there are two sequence numbers per millisecond
+	generator, err := NewGenerator(5, WithMachineIDBits(21), WithEpoch(time.UnixMilli(0)), WithClockInterface(clock))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	tests := []struct {
+		timestamp uint64
+		sequence  uint64
+		blocked   bool
+	}{
+		{timestamp: 367597485448, sequence: 0},
+		{timestamp: 367597485448, sequence: 1},
+		{timestamp: 367597485449, sequence: 0, blocked: true},
+	}
+	for _, tt := range tests {
+		id, err := generator.BlockingNextID(context.Background())
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, tt.timestamp, tt.sequence)
+		if generator.LastCallBlocked() != tt.blocked {
+			t.Errorf("expected blocked %v, got %v", tt.blocked, generator.LastCallBlocked())
+		}
+	}
+	// The sleep ends exactly at the start of the next millisecond
+	if want := time.UnixMilli(367597485449); !clock.Now().Equal(want) {
+		t.Errorf("expected %v, got %v", want, clock.Now())
+	}
+}
+
+// TestWithTimeFunc tests that the generator reads the time from the time function and waits with the sleep function
+func TestWithTimeFunc(t *testing.T) {
+	now := uint64(367597485448)
+	sleeps := 0
+	// With 21 machine ID bits there are two sequence numbers per millisecond
+	generator, err := NewGenerator(5, WithMachineIDBits(21), WithEpoch(time.UnixMilli(0)),
+		WithTimeFunc(func() uint64 {
+			return now
+		}),
+		WithSleepFunc(func() {
+			sleeps++
+			now++
+		}))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	for i, want := range []uint64{367597485448, 367597485448, 367597485449} {
+		id, err := generator.BlockingNextID(context.Background())
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, want, uint64(i%2))
+	}
+	if sleeps != 1 {
+		t.Errorf("expected 1 sleep, got %v", sleeps)
+	}
+}
+
+// TestWithTimeFunc_Nil tests that nil functions keep the default time and sleep functions
+func TestWithTimeFunc_Nil(t *testing.T) {
+	generator, err := NewGenerator(5, WithTimeFunc(nil), WithSleepFunc(nil))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	before := time.Now()
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.Time(id); got.Before(before.Truncate(time.Millisecond)) || got.After(time.Now()) {
+		t.Errorf("expected a time between %v and now, got %v", before, got)
+	}
+	generator.sleepFunc()
+}
+
+// TestManualClock tests that the manual clock only advances when it is told to
+func TestManualClock(t *testing.T) {
+	start := time.UnixMilli(1656432460105)
+	clock := NewManualClock(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("expected %v, got %v", start, clock.Now())
+	}
+	clock.Advance(time.Second)
+	clock.Sleep(time.Millisecond)
+	if want := start.Add(time.Second + time.Millisecond); !clock.Now().Equal(want) {
+		t.Errorf("expected %v, got %v", want, clock.Now())
+	}
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("expected %v, got %v", start, clock.Now())
+	}
+}
+
+// Example_manualClock reproduces a BlockingNextID call that blocks until the next millisecond with a ManualClock
+func Example_manualClock() {
+	clock := NewManualClock(time.UnixMilli(1656432460105))
+	// With 21 machine ID bits there are two sequence numbers per millisecond
+	generator, err := NewGenerator(5, WithMachineIDBits(21), WithEpoch(time.UnixMilli(0)), WithClockInterface(clock))
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < 3; i++ {
+		id, err := generator.BlockingNextID(context.Background())
+		if err != nil {
+			panic(err)
+		}
+		decoded := generator.DecodeID(id)
+		fmt.Println(decoded.Timestamp, decoded.Sequence, generator.LastCallBlocked())
+	}
+	// Output:
+	// 1656432460105 0 false
+	// 1656432460105 1 false
+	// 1656432460106 0 true
+}
@@ -0,0 +1,43 @@
+package snowflake
+
+import "sync/atomic"
+
+// maxClockGranularity is the largest clock step in milliseconds that is taken as the granularity of the clock, larger
+// steps are pauses between calls
+const maxClockGranularity = 100
+
+// coarseClock estimates the granularity of the clock from the steps between its readings
+type coarseClock struct {
+	last        atomic.Uint64
+	granularity atomic.Uint64
+}
+
+// observe records a reading of the clock, in milliseconds since the epoch
+// The granularity is the smallest step observed between two readings, steps larger than maxClockGranularity are
+// ignored
+func (c *coarseClock) observe(now uint64) {
+	last := c.last.Swap(now)
+	if last == 0 || now <= last || now-last > maxClockGranularity {
+		return
+	}
+	step := now - last
+	for {
+		granularity := c.granularity.Load()
+		if granularity != 0 && granularity <= step || c.granularity.CompareAndSwap(granularity, step) {
+			return
+		}
+	}
+}
+
+// WithCoarseClockCompensation compensates for clocks that advance in steps of more than one millisecond, such as the
+// 15.6ms timer of some Windows systems, which exhaust the sequence of a millisecond long before the clock moves on
+// The granularity of the clock is estimated as the smallest step between two readings of the clock, ignoring steps
+// of more than 100ms. When the sequence is exhausted the generator continues in the next milliseconds up to the
+// granularity ahead of the clock, as the real time is somewhere in between. On a clock with millisecond resolution
+// the estimate quickly drops to 1ms, which disables the compensation
+// With drift enabled the larger of the drift and the granularity is used
+func WithCoarseClockCompensation() Option {
+	return func(generator *Generator) {
+		generator.coarse = &coarseClock{}
+	}
+}
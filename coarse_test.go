@@ -0,0 +1,75 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestWithCoarseClockCompensation tests that a clock with 15ms steps continues in the milliseconds between the steps
+func TestWithCoarseClockCompensation(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantIDs int
+	}{
+		{name: "without compensation", wantIDs: 2},
+		// 2 IDs per millisecond for the millisecond of the clock and the 14 milliseconds until the next step
+		{name: "with compensation", opts: []Option{WithCoarseClockCompensation()}, wantIDs: 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append(tt.opts, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(21))
+			generator, err := NewGenerator(1, opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			for _, now := range []uint64{1000, 1015} {
+				now := now
+				generator.SetTimeFunc(func() uint64 {
+					return now
+				})
+				if _, err = generator.NextID(); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+			// The clock is at 1015 and one ID of it is generated, exhaust the milliseconds that are allowed
+			count := 1
+			var previous ID
+			for {
+				id, err := generator.NextID()
+				if errors.Is(err, ErrOutOfSequence) {
+					break
+				}
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				if id <= previous {
+					t.Errorf("expected %v to be greater than %v", uint64(id), uint64(previous))
+				}
+				previous = id
+				count++
+			}
+			if count != tt.wantIDs {
+				t.Errorf("expected %v IDs, got %v", tt.wantIDs, count)
+			}
+			if tt.wantIDs > 2 && generator.DecodeID(previous).Timestamp != 1029 {
+				t.Errorf("expected the last ID at 1029, got %v", generator.DecodeID(previous))
+			}
+		})
+	}
+}
+
+// TestCoarseClock_Observe tests that the granularity is the smallest step between readings
+func TestCoarseClock_Observe(t *testing.T) {
+	var c coarseClock
+	for _, now := range []uint64{1000, 1031, 1031, 1047, 1500, 1515} {
+		c.observe(now)
+	}
+	if got := c.granularity.Load(); got != 15 {
+		t.Errorf("expected 15, got %v", got)
+	}
+}
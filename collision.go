@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoCollisionRecovery is returned when a machine ID collision is reported without WithMachineIDCollisionRecovery
+	ErrNoCollisionRecovery = errors.New("machine ID collision recovery is not enabled")
+	// ErrMachineIDCollision is returned when the collision recovery provider returns the colliding machine ID
+	ErrMachineIDCollision = errors.New("machine ID collision")
+)
+
+// WithMachineIDCollisionRecovery sets a provider that is called by RecoverMachineIDCollision to get a free machine ID
+// The provider is usually backed by the same shared store that detects the collision
+func WithMachineIDCollisionRecovery(provider MachineIDProvider) Option {
+	return func(generator *Generator) {
+		generator.recovery = provider
+	}
+}
+
+// RecoverMachineIDCollision switches the generator to a free machine ID from the collision recovery provider
+// Call it when another instance is detected to use the machine ID of the generator, for example by the client of the
+// shared store that assigns the machine IDs. IDs generated before the switch have the old machine ID and IDs
+// generated after it have the new machine ID, the sequence continues, so the IDs of the generator stay unique as long
+// as the new machine ID is not used by another instance. An ID generated concurrently with the switch gets
+// either machine ID. The collision is counted in the MachineIDCollisions of Stats
+// Returns ErrNoCollisionRecovery if WithMachineIDCollisionRecovery is not set, the error of the provider or the
+// context, ErrMachineIDTooLarge if the new machine ID does not fit and ErrMachineIDCollision if it is the same machine
+// ID. The machine ID is unchanged when an error is returned
+func (g *Generator) RecoverMachineIDCollision(ctx context.Context) error {
+	if g.recovery == nil {
+		return ErrNoCollisionRecovery
+	}
+	id, err := provideMachineID(ctx, g.recovery)
+	if err != nil {
+		return err
+	}
+	if id > g.machineIDMask {
+		return machineIDTooLarge(id, g.layout.MachineIDBits)
+	}
+	if old := g.machineID.Swap(id); old == id {
+		return fmt.Errorf("%w: provider returned machine ID %d again", ErrMachineIDCollision, id)
+	}
+	g.collisions.Add(1)
+	return nil
+}
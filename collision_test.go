@@ -0,0 +1,98 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestGenerator_RecoverMachineIDCollision tests that the generator switches to the machine ID of the provider
+func TestGenerator_RecoverMachineIDCollision(t *testing.T) {
+	generator, err := NewGenerator(1, WithEpoch(time.UnixMilli(0)), WithMachineIDCollisionRecovery(func(ctx context.Context) (uint64, error) {
+		return 2, nil
+	}))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	before, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if err = generator.RecoverMachineIDCollision(context.Background()); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	after, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.DecodeID(before); got.MachineID != 1 || got.Sequence != 0 {
+		t.Errorf("expected machine ID 1 and sequence 0, got %v", got)
+	}
+	if got := generator.DecodeID(after); got.MachineID != 2 || got.Sequence != 1 {
+		t.Errorf("expected machine ID 2 and sequence 1, got %v", got)
+	}
+	if stats := generator.Stats(); stats.MachineID != 2 || stats.MachineIDCollisions != 1 {
+		t.Errorf("expected machine ID 2 and 1 collision, got %+v", stats)
+	}
+}
+
+// TestGenerator_RecoverMachineIDCollision_Errors tests that the machine ID is unchanged when the recovery fails
+func TestGenerator_RecoverMachineIDCollision_Errors(t *testing.T) {
+	errStore := errors.New("store unavailable")
+	tests := []struct {
+		name     string
+		provider MachineIDProvider
+		want     error
+	}{
+		{name: "not enabled", want: ErrNoCollisionRecovery},
+		{
+			name: "provider error",
+			provider: func(ctx context.Context) (uint64, error) {
+				return 0, errStore
+			},
+			want: errStore,
+		},
+		{
+			name: "machine ID too large",
+			provider: func(ctx context.Context) (uint64, error) {
+				return 1 << 10, nil
+			},
+			want: ErrMachineIDTooLarge,
+		},
+		{
+			name: "same machine ID",
+			provider: func(ctx context.Context) (uint64, error) {
+				return 1, nil
+			},
+			want: ErrMachineIDCollision,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.provider != nil {
+				opts = append(opts, WithMachineIDCollisionRecovery(tt.provider))
+			}
+			generator, err := NewGenerator(1, opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if err = generator.RecoverMachineIDCollision(context.Background()); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if stats := generator.Stats(); stats.MachineID != 1 || stats.MachineIDCollisions != 0 {
+				t.Errorf("expected machine ID 1 and no collisions, got %+v", stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompareByTime compares the timestamps of two IDs with the given number of machine ID bits
+// Returns -1 if a is older than b, 1 if a is newer than b and 0 if both are from the same millisecond, regardless of
+// their machine ID and sequence
+func CompareByTime(a, b ID, machineIDBits uint64) int {
+	ta := a.TimestampDelta(machineIDBits)
+	tb := b.TimestampDelta(machineIDBits)
+	switch {
+	case ta < tb:
+		return -1
+	case ta > tb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// TimestampDelta returns the raw timestamp of the ID with the given number of machine ID bits, which is the number of
+// milliseconds since the epoch of the generator
+func (id ID) TimestampDelta(machineIDBits uint64) uint64 {
+	layout := Layout{MachineIDBits: machineIDBits}
+	return uint64(id) >> layout.timestampShift() & layout.timestampMask()
+}
+
+// Split returns the raw timestamp, machine ID and sequence of the ID with the given number of machine ID bits
+// It is the low level variant of DecodeID for the default layout, which decodes the ID without building a DecodedID
+func (id ID) Split(machineIDBits uint64) (timestampDelta, machineID, sequence uint64) {
+	layout := Layout{MachineIDBits: machineIDBits}
+	timestampDelta = uint64(id) >> layout.timestampShift() & layout.timestampMask()
+	machineID = uint64(id) >> layout.machineIDShift() & layout.machineIDMask()
+	sequence = uint64(id) >> layout.sequenceShift() & layout.sequenceMask()
+	return timestampDelta, machineID, sequence
+}
+
+// EqualIgnoringMachine reports whether two IDs with the given number of machine ID bits have the same timestamp and
+// sequence, regardless of their machine ID
+// This is not general equality: IDs from different generators that are equal this way are distinct IDs that only
+// happen to share a tick. It is meant for deduplicating events that were assigned IDs independently by generators
+// of an active-active setup that issue the same timestamp and sequence for the same logical event
+func EqualIgnoringMachine(a, b ID, machineIDBits uint64) bool {
+	ta, _, sa := a.Split(machineIDBits)
+	tb, _, sb := b.Split(machineIDBits)
+	return ta == tb && sa == sb
+}
+
+// TruncateToTimestamp returns the ID with the given number of machine ID bits with the machine ID and sequence bits
+// cleared, IDs from the same millisecond are equal after truncation
+func (id ID) TruncateToTimestamp(machineIDBits uint64) ID {
+	layout := Layout{MachineIDBits: machineIDBits}
+	return ID(uint64(id) &^ (1<<layout.timestampShift() - 1))
+}
+
+// DiffIDs returns a table of the components of two IDs with the given layout side by side, to explain their order
+// The components are listed from the most to the least significant bits, a component that differs is marked with <
+// or > for the order of a and b. The first differing component decides the numeric order of the IDs
+func DiffIDs(a, b ID, layout Layout) string {
+	var sb strings.Builder
+	line := func(name, x, y, marker string) {
+		sb.WriteString(strings.TrimRight(fmt.Sprintf("%-10s %-20s %-20s %s", name, x, y, marker), " ") + "\n")
+	}
+	line("", "a", "b", "")
+	row := func(name string, x, y uint64) {
+		marker := ""
+		switch {
+		case x < y:
+			marker = "<"
+		case x > y:
+			marker = ">"
+		}
+		line(name, fmt.Sprint(x), fmt.Sprint(y), marker)
+	}
+	row("ID", uint64(a), uint64(b))
+	for _, f := range layout.fields() {
+		mask := uint64(1)<<f.bits - 1
+		row(f.name, uint64(a)>>f.shift&mask, uint64(b)>>f.shift&mask)
+	}
+	return sb.String()
+}
@@ -0,0 +1,151 @@
+package snowflake
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCompareByTime tests the CompareByTime function
+func TestCompareByTime(t *testing.T) {
+	tests := []struct {
+		name string
+		a    ID
+		b    ID
+		want int
+	}{
+		{name: "older", a: 1<<22 | 1023<<12 | 4095, b: 2 << 22, want: -1},
+		{name: "newer", a: 2 << 22, b: 1<<22 | 1023<<12 | 4095, want: 1},
+		{name: "same millisecond, different machine ID", a: 1<<22 | 1<<12, b: 1<<22 | 2<<12, want: 0},
+		{name: "same millisecond, different sequence", a: 1<<22 | 5, b: 1<<22 | 3, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareByTime(tt.a, tt.b, 10); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestEqualIgnoringMachine tests that only the timestamp and sequence are compared
+func TestEqualIgnoringMachine(t *testing.T) {
+	tests := []struct {
+		name string
+		a    ID
+		b    ID
+		want bool
+	}{
+		{name: "equal", a: 1<<22 | 1<<12 | 5, b: 1<<22 | 1<<12 | 5, want: true},
+		{name: "different machine ID", a: 1<<22 | 1<<12 | 5, b: 1<<22 | 1023<<12 | 5, want: true},
+		{name: "different sequence", a: 1<<22 | 1<<12 | 5, b: 1<<22 | 1<<12 | 6, want: false},
+		{name: "different timestamp", a: 1<<22 | 1<<12 | 5, b: 2<<22 | 1<<12 | 5, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualIgnoringMachine(tt.a, tt.b, 10); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestID_TimestampDelta tests the TimestampDelta method of the ID type
+func TestID_TimestampDelta(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            ID
+		machineIDBits uint64
+		want          uint64
+	}{
+		{name: "Twitter test vector", id: 1541815603606036480, machineIDBits: 10, want: 367597485448},
+		{name: "21 machine ID bits", id: 5<<22 | 1<<21 - 1<<1 | 1, machineIDBits: 21, want: 5},
+		{name: "maximum timestamp", id: 1<<64 - 1, machineIDBits: 10, want: 1<<42 - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.TimestampDelta(tt.machineIDBits); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestID_TruncateToTimestamp tests that IDs of the same millisecond are equal after truncation
+func TestID_TruncateToTimestamp(t *testing.T) {
+	a := ID(367597485448<<22 | 378<<12 | 7)
+	b := ID(367597485448<<22 | 1<<12 | 4095)
+	if a.TruncateToTimestamp(10) != b.TruncateToTimestamp(10) {
+		t.Errorf("expected %v, got %v", uint64(a.TruncateToTimestamp(10)), uint64(b.TruncateToTimestamp(10)))
+	}
+	if want := ID(367597485448 << 22); a.TruncateToTimestamp(10) != want {
+		t.Errorf("expected %v, got %v", uint64(want), uint64(a.TruncateToTimestamp(10)))
+	}
+	c := ID(367597485449 << 22)
+	if a.TruncateToTimestamp(10) == c.TruncateToTimestamp(10) {
+		t.Errorf("expected IDs of different milliseconds to differ after truncation")
+	}
+}
+
+// TestID_Split tests that Split returns the same components as DecodeID
+func TestID_Split(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            ID
+		machineIDBits uint64
+	}{
+		{name: "Twitter test vector", id: 1541815603606036480, machineIDBits: 10},
+		{name: "1 machine ID bit", id: 367597485448<<22 | 1<<21 | 7, machineIDBits: 1},
+		{name: "21 machine ID bits", id: 5<<22 | 1<<21 - 1<<1 | 1, machineIDBits: 21},
+		{name: "maximum ID", id: 1<<63 - 1, machineIDBits: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := DecodeID(tt.id, Layout{MachineIDBits: tt.machineIDBits})
+			timestampDelta, machineID, sequence := tt.id.Split(tt.machineIDBits)
+			if timestampDelta != want.Timestamp || machineID != want.MachineID || sequence != want.Sequence {
+				t.Errorf("expected %v, %v, %v, got %v, %v, %v", want.Timestamp, want.MachineID, want.Sequence,
+					timestampDelta, machineID, sequence)
+			}
+		})
+	}
+}
+
+// TestDiffIDs tests the DiffIDs function against golden files
+func TestDiffIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		a      ID
+		b      ID
+		layout Layout
+	}{
+		{name: "sequence", a: 1541815603606036480, b: 1541815603606036481, layout: DefaultLayout()},
+		{name: "timestamp", a: 367597485449<<22 | 1<<12, b: 367597485448<<22 | 378<<12 | 7, layout: DefaultLayout()},
+		{
+			name:   "spread",
+			a:      1<<63 | 7<<51 | 5<<10 | 3<<5 | 2,
+			b:      1<<63 | 6<<51 | 9<<10 | 3<<5 | 2,
+			layout: Layout{VersionBit: true, MachineIDBits: 5, ShardBits: 5, Order: OrderSequenceTimestampMachine},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffIDs(tt.a, tt.b, tt.layout)
+			golden := filepath.Join("testdata", "diff_"+tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got != string(want) {
+				t.Errorf("expected\n%v\ngot\n%v", string(want), got)
+			}
+		})
+	}
+}
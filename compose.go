@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNonceTooLarge is returned when a nonce is too large for the number of nonce bits
+	ErrNonceTooLarge = errors.New("nonce is too large")
+)
+
+// ComposeID composes a snowflake ID from its components using the given layout, it is the inverse of DecodeID
+// The ID field of the components is ignored
+// Every component is checked against the width of its field, so a component never overflows into another field
+// Returns an error wrapped with the offending value if the layout is invalid or a component does not fit
+func ComposeID(components DecodedID, layout Layout) (ID, error) {
+	if err := layout.validate(); err != nil {
+		return 0, err
+	}
+	fields := []struct {
+		value uint64
+		mask  uint64
+		err   error
+	}{
+		{value: components.Version, mask: layout.versionBits(), err: ErrVersionTooLarge},
+		{value: components.Timestamp, mask: layout.timestampMask(), err: ErrTimestampOverflow},
+		{value: components.MachineID, mask: layout.machineIDMask(), err: ErrMachineIDTooLarge},
+		{value: components.Shard, mask: layout.shardMask(), err: ErrShardTooLarge},
+		{value: components.Nonce, mask: layout.nonceMask(), err: ErrNonceTooLarge},
+		{value: components.Sequence, mask: layout.sequenceMask(), err: ErrSequenceTooLarge},
+	}
+	for _, f := range fields {
+		if f.value > f.mask {
+			return 0, fmt.Errorf("%w: %d does not fit in %d", f.err, f.value, f.mask)
+		}
+	}
+	return ID(components.Version<<63 |
+		components.Timestamp<<layout.timestampShift() |
+		components.MachineID<<layout.machineIDShift() |
+		components.Shard<<layout.shardShift() |
+		components.Nonce<<layout.nonceShift() |
+		components.Sequence<<layout.sequenceShift()), nil
+}
+
+// IDAtTime composes the ID with the given time and sequence using the layout, machine ID, version and nonce of the
+// generator, without changing the state of the generator
+// Returns an error if the time is before the epoch or a component does not fit in its field
+func (g *Generator) IDAtTime(t time.Time, sequence uint64) (ID, error) {
+	timestamp := g.ticks(t) - g.epoch
+	if timestamp < 0 {
+		return 0, ErrTimeBeforeEpoch
+	}
+	return ComposeID(DecodedID{
+		Version:   g.version,
+		Timestamp: uint64(timestamp),
+		MachineID: g.machineID.Load(),
+		Nonce:     g.nonce,
+		Sequence:  sequence,
+	}, g.layout)
+}
@@ -0,0 +1,158 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestComposeID tests that ComposeID is the inverse of DecodeID for extreme layouts
+func TestComposeID(t *testing.T) {
+	layouts := []Layout{
+		DefaultLayout(),
+		{MachineIDBits: 1},
+		{MachineIDBits: 21},
+		{VersionBit: true, MachineIDBits: 21},
+		{VersionBit: true, MachineIDBits: 5, ShardBits: 5, NonceBits: 5},
+		{MachineIDBits: 10, ShardBits: 4, Order: OrderSequenceTimestampMachine},
+		{VersionBit: true, MachineIDBits: 1, NonceBits: 20, Order: OrderSequenceTimestampMachine},
+		{MachineIDBits: 10, ShardBits: 4, Order: OrderTimestampSequenceMachine},
+		{VersionBit: true, MachineIDBits: 5, ShardBits: 5, NonceBits: 5, Order: OrderTimestampSequenceMachine},
+	}
+	for _, layout := range layouts {
+		// All fields at their maximum must set every bit, any overlap or gap would show
+		max := DecodedID{
+			Version:   layout.versionBits(),
+			Timestamp: layout.timestampMask(),
+			MachineID: layout.machineIDMask(),
+			Shard:     layout.shardMask(),
+			Nonce:     layout.nonceMask(),
+			Sequence:  layout.sequenceMask(),
+		}
+		min := DecodedID{}
+		for _, components := range []DecodedID{max, min} {
+			id, err := ComposeID(components, layout)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			components.ID = uint64(id)
+			if got := DecodeID(id, layout); got != components {
+				t.Errorf("got %v, want %v", got, components)
+			}
+		}
+		if id, _ := ComposeID(max, layout); id != 1<<64-1 {
+			t.Errorf("expected all bits to be set for %+v, got %b", layout, uint64(id))
+		}
+	}
+}
+
+// TestComposeID_Errors tests that ComposeID rejects components that do not fit in their field
+func TestComposeID_Errors(t *testing.T) {
+	layout := Layout{VersionBit: true, MachineIDBits: 5, ShardBits: 5, NonceBits: 5}
+	tests := []struct {
+		name       string
+		components DecodedID
+		wantErr    error
+	}{
+		{name: "version", components: DecodedID{Version: 2}, wantErr: ErrVersionTooLarge},
+		{name: "timestamp", components: DecodedID{Timestamp: 1 << 41}, wantErr: ErrTimestampOverflow},
+		{name: "machine ID", components: DecodedID{MachineID: 1 << 5}, wantErr: ErrMachineIDTooLarge},
+		{name: "shard", components: DecodedID{Shard: 1 << 5}, wantErr: ErrShardTooLarge},
+		{name: "nonce", components: DecodedID{Nonce: 1 << 5}, wantErr: ErrNonceTooLarge},
+		{name: "sequence", components: DecodedID{Sequence: 1 << 7}, wantErr: ErrSequenceTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ComposeID(tt.components, layout); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if _, err := ComposeID(DecodedID{}, Layout{}); !errors.Is(err, ErrMachineBitsTooSmall) {
+		t.Errorf("expected ErrMachineBitsTooSmall, got %v", err)
+	}
+}
+
+// TestGenerator_IDAtTime tests that IDAtTime composes the ID that NextID generates for the same time and sequence
+func TestGenerator_IDAtTime(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 1656432460105
+	})
+	for sequence := uint64(0); sequence < 3; sequence++ {
+		want, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		got, err := generator.IDAtTime(time.UnixMilli(1656432460105), sequence)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if got != want {
+			t.Errorf("expected %v, got %v", uint64(want), uint64(got))
+		}
+	}
+	if _, err = generator.IDAtTime(time.UnixMilli(1288834974656), 0); !errors.Is(err, ErrTimeBeforeEpoch) {
+		t.Errorf("expected ErrTimeBeforeEpoch, got %v", err)
+	}
+	if _, err = generator.IDAtTime(time.UnixMilli(1656432460105), 4096); !errors.Is(err, ErrSequenceTooLarge) {
+		t.Errorf("expected ErrSequenceTooLarge, got %v", err)
+	}
+}
+
+// FuzzComposeID tests that DecodeID(ComposeID()) yields the composed components for random layouts and components
+func FuzzComposeID(f *testing.F) {
+	// Twitter
+	f.Add(true, uint64(0), uint64(10), uint64(0), uint64(0), uint8(0), uint64(367597485448), uint64(378), uint64(0),
+		uint64(0), uint64(0))
+	// Sonyflake
+	f.Add(false, uint64(39), uint64(16), uint64(0), uint64(0), uint8(2), uint64(1<<39-1), uint64(1<<16-1), uint64(0),
+		uint64(0), uint64(255))
+	// Discord, with the worker ID as machine ID and the process ID as shard
+	f.Add(false, uint64(0), uint64(5), uint64(5), uint64(0), uint8(0), uint64(175928847299), uint64(1), uint64(2),
+		uint64(0), uint64(7))
+	// All bits for the timestamp
+	f.Add(true, uint64(62), uint64(0), uint64(0), uint64(0), uint8(1), uint64(1<<62-1), uint64(0), uint64(0), uint64(0),
+		uint64(0))
+	f.Fuzz(func(t *testing.T, versionBit bool, timestampBits, machineIDBits, shardBits, nonceBits uint64, order uint8,
+		timestamp, machineID, shard, nonce, sequence uint64) {
+		layout := Layout{
+			VersionBit:    versionBit,
+			TimestampBits: timestampBits % 63,
+			Order:         FieldOrder(order % 3),
+		}
+		// At least one bit is left for the sequence
+		fieldBits := layout.fieldBits()
+		machineIDBits %= fieldBits
+		shardBits %= fieldBits - machineIDBits
+		nonceBits %= fieldBits - machineIDBits - shardBits
+		layout.MachineIDBits = machineIDBits
+		layout.ShardBits = shardBits
+		layout.NonceBits = nonceBits
+		layout.SingleNode = machineIDBits == 0
+		want := DecodedID{
+			Version:   layout.versionBits(),
+			Timestamp: timestamp & layout.timestampMask(),
+			MachineID: machineID & layout.machineIDMask(),
+			Shard:     shard & layout.shardMask(),
+			Nonce:     nonce & layout.nonceMask(),
+			Sequence:  sequence & layout.sequenceMask(),
+		}
+		id, err := ComposeID(want, layout)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		want.ID = uint64(id)
+		if got := DecodeID(id, layout); got != want {
+			t.Errorf("got %v, want %v for layout %+v", got, want, layout)
+		}
+	})
+}
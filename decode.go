@@ -1,26 +1,216 @@
 package snowflake
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
-// DecodedID is a snowflake ID decoded into its components
+var (
+	// ErrBufferTooShort is returned when the output slice cannot hold a result for every ID
+	ErrBufferTooShort = errors.New("buffer is too short")
+)
+
+var (
+	// TwitterEpoch is the epoch of Twitter snowflake IDs, decode them with the twitter layout: a reserved sign bit and
+	// 10 machine ID bits
+	TwitterEpoch = time.UnixMilli(1288834974657).UTC()
+	// DiscordEpoch is the epoch of Discord snowflake IDs, the first second of 2015, decode them with 10 machine ID bits
+	DiscordEpoch = time.UnixMilli(1420070400000).UTC()
+	// InstagramEpoch is the epoch of Instagram IDs, decode them with Layout{TimestampBits: 40, MachineIDBits: 13} for
+	// the 13 shard bits and 10 sequence bits, Instagram uses 41 timestamp bits but the highest is zero until 2046
+	InstagramEpoch = time.UnixMilli(1314220021721).UTC()
+	// SonyflakeEpoch is the default epoch of Sonyflake IDs, 2014-09-01, create generators for them with the sonyflake
+	// layout of NewGeneratorNamed
+	SonyflakeEpoch = time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// DecodedID is a snowflake ID decoded into its components, which DecodeID returns
+// The fields can be used directly, e.g. to route records by MachineID, fields the layout does not have are zero
 type DecodedID struct {
-	ID        uint64
+	// ID is the snowflake ID itself
+	ID uint64
+	// Version is the value of the version bit
+	Version uint64
+	// Timestamp is the number of milliseconds since the epoch
 	Timestamp uint64
+	// MachineID is the machine ID of the generator that generated the ID
 	MachineID uint64
-	Sequence  uint64
+	// Shard is the shard of NextIDForShard
+	Shard uint64
+	// Nonce is the instance nonce of the generator
+	Nonce uint64
+	// Sequence is the sequence number within the millisecond
+	Sequence uint64
 }
 
 // String returns a string representation of the decoded ID
+// The version, shard and nonce are only included when they are not zero
 func (id DecodedID) String() string {
-	return fmt.Sprintf("ID: %d, Timestamp: %d, MachineID: %d, Sequence: %d", id.ID, id.Timestamp, id.MachineID, id.Sequence)
+	s := fmt.Sprintf("ID: %d, ", id.ID)
+	if id.Version != 0 {
+		s += fmt.Sprintf("Version: %d, ", id.Version)
+	}
+	s += fmt.Sprintf("Timestamp: %d, MachineID: %d, ", id.Timestamp, id.MachineID)
+	if id.Shard != 0 {
+		s += fmt.Sprintf("Shard: %d, ", id.Shard)
+	}
+	if id.Nonce != 0 {
+		s += fmt.Sprintf("Nonce: %d, ", id.Nonce)
+	}
+	return s + fmt.Sprintf("Sequence: %d", id.Sequence)
 }
 
 // DecodeID decodes a snowflake ID into its components
 func (g *Generator) DecodeID(id ID) DecodedID {
+	return DecodeID(id, g.layout)
+}
+
+// DecodeID decodes a snowflake ID into its components using the given layout
+// It does not need a generator, which makes it suitable for tools that only decode IDs
+func DecodeID(id ID, layout Layout) DecodedID {
 	return DecodedID{
 		ID:        uint64(id),
-		Timestamp: uint64(id) >> timeShift,
-		MachineID: uint64(id) >> g.machineIDShift & g.machineIDMask,
-		Sequence:  uint64(id) & g.sequenceMask,
+		Version:   uint64(id) >> 63 & layout.versionBits(),
+		Timestamp: uint64(id) >> layout.timestampShift() & layout.timestampMask(),
+		MachineID: uint64(id) >> layout.machineIDShift() & layout.machineIDMask(),
+		Shard:     uint64(id) >> layout.shardShift() & layout.shardMask(),
+		Nonce:     uint64(id) >> layout.nonceShift() & layout.nonceMask(),
+		Sequence:  uint64(id) >> layout.sequenceShift() & layout.sequenceMask(),
+	}
+}
+
+// Components is a snowflake ID decoded into its components and the time at which it was generated
+// The fields of DecodedID are promoted, so the machine ID of components c is c.MachineID
+type Components struct {
+	DecodedID
+	// Time is the time at which the ID was generated in UTC
+	Time time.Time
+}
+
+// String returns a string representation of the decoded ID like DecodedID, followed by the time
+func (c Components) String() string {
+	return c.DecodedID.String() + ", Time: " + c.Time.Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// Components decodes a snowflake ID into its components and the time at which it was generated, using the epoch and
+// layout of the generator
+func (g *Generator) Components(id ID) Components {
+	return Components{DecodedID: g.DecodeID(id), Time: g.Time(id)}
+}
+
+// DecodeIDs decodes every ID into its components and time like Components, in the order of ids
+// The shifts and masks of the layout are computed once for all IDs. Returns an empty slice for an empty or nil ids
+func (g *Generator) DecodeIDs(ids []ID) []Components {
+	l := g.layout
+	versionMask, timestampShift, timestampMask := l.versionBits(), l.timestampShift(), l.timestampMask()
+	machineIDShift, machineIDMask := l.machineIDShift(), l.machineIDMask()
+	shardShift, shardMask := l.shardShift(), l.shardMask()
+	nonceShift, nonceMask := l.nonceShift(), l.nonceMask()
+	sequenceShift, sequenceMask := l.sequenceShift(), l.sequenceMask()
+	components := make([]Components, len(ids))
+	for i, id := range ids {
+		v := uint64(id)
+		timestamp := v >> timestampShift & timestampMask
+		components[i] = Components{
+			DecodedID: DecodedID{
+				ID:        v,
+				Version:   v >> 63 & versionMask,
+				Timestamp: timestamp,
+				MachineID: v >> machineIDShift & machineIDMask,
+				Shard:     v >> shardShift & shardMask,
+				Nonce:     v >> nonceShift & nonceMask,
+				Sequence:  v >> sequenceShift & sequenceMask,
+			},
+			Time: g.timeOf(g.epoch + int64(timestamp)).UTC(),
+		}
+	}
+	return components
+}
+
+// DecodeWithEpoch decodes a snowflake ID into its components using the given epoch and layout
+// It does not need a generator, which makes it suitable for IDs of other systems, such as TwitterEpoch with the
+// twitter layout
+func DecodeWithEpoch(id ID, epoch time.Time, layout Layout) Components {
+	decoded := DecodeID(id, layout)
+	return Components{
+		DecodedID: decoded,
+		Time:      time.UnixMilli(epoch.UnixMilli() + int64(decoded.Timestamp)).UTC(),
+	}
+}
+
+// RawDecoded is a snowflake ID decoded into its raw fields, the timestamp is the number of milliseconds since an
+// unknown epoch
+type RawDecoded = DecodedID
+
+// DecodeRaw decodes a snowflake ID into its raw fields using the layout of the generator, it does not use the epoch
+// This is useful for IDs of systems with an unknown epoch
+func (g *Generator) DecodeRaw(id ID) RawDecoded {
+	return DecodeID(id, g.layout)
+}
+
+// DecodeString parses a decimal string and decodes the snowflake ID into its components
+// Returns an error if the string is not a valid ID
+func (g *Generator) DecodeString(s string) (DecodedID, error) {
+	id, err := ParseID(s)
+	if err != nil {
+		return DecodedID{}, err
+	}
+	return g.DecodeID(id), nil
+}
+
+// Timestamp returns the raw timestamp of the ID, which is the number of milliseconds since the epoch of the generator
+func (g *Generator) Timestamp(id ID) uint64 {
+	return uint64(id) >> g.layout.timestampShift() & g.layout.timestampMask()
+}
+
+// Time returns the time at which the ID was generated in UTC, which is the timestamp of the ID added to the epoch
+func (g *Generator) Time(id ID) time.Time {
+	return g.timeOf(g.epoch + int64(g.Timestamp(id))).UTC()
+}
+
+// Timestamps returns the time of every ID in UTC like Time, without decoding the other components
+func (g *Generator) Timestamps(ids []ID) []time.Time {
+	shift, mask := g.layout.timestampShift(), g.layout.timestampMask()
+	times := make([]time.Time, len(ids))
+	for i, id := range ids {
+		times[i] = g.timeOf(g.epoch + int64(uint64(id)>>shift&mask)).UTC()
+	}
+	return times
+}
+
+// DecodeTimestampsInto writes the time of every ID in UTC to out like Timestamps, without allocating
+// Only the first len(ids) elements of out are written, so out can be reused between calls
+// Returns ErrBufferTooShort if out is shorter than ids, out is then left unchanged
+func (g *Generator) DecodeTimestampsInto(ids []ID, out []time.Time) error {
+	if len(out) < len(ids) {
+		return fmt.Errorf("%w: %d IDs do not fit in %d times", ErrBufferTooShort, len(ids), len(out))
+	}
+	shift, mask := g.layout.timestampShift(), g.layout.timestampMask()
+	for i, id := range ids {
+		out[i] = g.timeOf(g.epoch + int64(uint64(id)>>shift&mask)).UTC()
+	}
+	return nil
+}
+
+// SpanMilliseconds returns the number of distinct milliseconds of the IDs, the IDs do not have to be sorted
+// A burst of IDs in few milliseconds was close to exhausting the sequence, a burst over many milliseconds was not
+func (g *Generator) SpanMilliseconds(ids []ID) uint64 {
+	shift, mask := g.layout.timestampShift(), g.layout.timestampMask()
+	seen := make(map[uint64]struct{})
+	for _, id := range ids {
+		seen[uint64(id)>>shift&mask] = struct{}{}
+	}
+	return uint64(len(seen))
+}
+
+// Bucket returns the number of whole windows between the generator epoch and the time of the ID, e.g. days since
+// the epoch for a window of 24 hours, so the buckets align with the epoch
+// Returns ErrInvalidWindow wrapped with the window if it is shorter than the time unit of the generator
+func (g *Generator) Bucket(id ID, window time.Duration) (int64, error) {
+	if window < g.unit {
+		return 0, fmt.Errorf("%w: %v is shorter than the time unit %v", ErrInvalidWindow, window, g.unit)
 	}
+	timestamp := uint64(id) >> g.layout.timestampShift() & g.layout.timestampMask()
+	return int64(timestamp / g.units(window)), nil
 }
@@ -1,6 +1,8 @@
 package snowflake
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -22,6 +24,39 @@ func TestDecodedID_String(t *testing.T) {
 			},
 			want: "ID: 1, Timestamp: 2, MachineID: 3, Sequence: 4",
 		},
+		{
+			name: "Test DecodedID String method with shard",
+			id: DecodedID{
+				ID:        1,
+				Timestamp: 2,
+				MachineID: 3,
+				Shard:     5,
+				Sequence:  4,
+			},
+			want: "ID: 1, Timestamp: 2, MachineID: 3, Shard: 5, Sequence: 4",
+		},
+		{
+			name: "Test DecodedID String method with version",
+			id: DecodedID{
+				ID:        1,
+				Version:   1,
+				Timestamp: 2,
+				MachineID: 3,
+				Sequence:  4,
+			},
+			want: "ID: 1, Version: 1, Timestamp: 2, MachineID: 3, Sequence: 4",
+		},
+		{
+			name: "Test DecodedID String method with nonce",
+			id: DecodedID{
+				ID:        1,
+				Timestamp: 2,
+				MachineID: 3,
+				Nonce:     6,
+				Sequence:  4,
+			},
+			want: "ID: 1, Timestamp: 2, MachineID: 3, Nonce: 6, Sequence: 4",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -76,9 +111,9 @@ func TestGenerator_DecodeID(t *testing.T) {
 				t.Errorf("expected no error, got %v", err)
 				return
 			}
-			g.timeFunc = func() uint64 {
+			g.SetTimeFunc(func() uint64 {
 				return tt.timestamp
-			}
+			})
 			var id ID
 			for i := 0; i < tt.sequence; i++ {
 				id, err = g.NextID()
@@ -93,3 +128,465 @@ func TestGenerator_DecodeID(t *testing.T) {
 		})
 	}
 }
+
+// verifyRoundTrip checks that decoding an ID yields the components it was generated with
+func verifyRoundTrip(t *testing.T, g *Generator, id ID, timestamp uint64, sequence uint64) {
+	t.Helper()
+	want := DecodedID{
+		ID:        uint64(id),
+		Timestamp: timestamp,
+		MachineID: g.MachineID(),
+		Sequence:  sequence,
+	}
+	if got := g.DecodeID(id); got != want {
+		t.Errorf("DecodeID(%v) = %v, want %v", uint64(id), got, want)
+	}
+}
+
+// FuzzGenerator_DecodeID tests that DecodeID(NextID()) yields the encoded components for random layouts and timestamps
+func FuzzGenerator_DecodeID(f *testing.F) {
+	f.Add(uint64(10), uint64(378), uint64(367597485448), uint16(1))
+	f.Add(uint64(1), uint64(1), uint64(0), uint16(4096))
+	f.Add(uint64(21), uint64(1<<21-1), uint64(1<<42-1), uint16(3))
+	f.Fuzz(func(t *testing.T, machineIDBits uint64, machineID uint64, timestamp uint64, count uint16) {
+		machineIDBits = machineIDBits%21 + 1
+		machineID = machineID & (1<<machineIDBits - 1)
+		// The zero state of the generator is indistinguishable from an ID at the epoch millisecond, so the first
+		// millisecond starts at sequence 1. Start at 1 to keep the expected sequence simple.
+		timestamp = timestamp%(1<<(64-timeShift)-1) + 1
+		g, err := NewGenerator(machineID, WithMachineIDBits(machineIDBits), WithEpoch(time.UnixMilli(0)))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		g.SetTimeFunc(func() uint64 {
+			return timestamp
+		})
+		for sequence := uint64(0); sequence < uint64(count) && sequence <= g.sequenceMask; sequence++ {
+			id, err := g.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			verifyRoundTrip(t, g, id, timestamp, sequence)
+		}
+	})
+}
+
+// TestGenerator_DecodeString tests the Generator DecodeString method
+func TestGenerator_DecodeString(t *testing.T) {
+	g, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	got, err := g.DecodeString("1541815603606036480")
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	want := DecodedID{ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err = g.DecodeString("not an id"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+// TestDecodeID tests the DecodeID function without a generator
+func TestDecodeID(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     ID
+		layout Layout
+		want   DecodedID
+	}{
+		{
+			name:   "Test DecodeID with the default layout",
+			id:     1541815603606036480,
+			layout: DefaultLayout(),
+			want:   DecodedID{ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378},
+		},
+		{
+			name:   "Test DecodeID with shard and version bits",
+			id:     1<<63 | 5<<22 | 3<<17 | 2<<12 | 7,
+			layout: Layout{VersionBit: true, MachineIDBits: 5, ShardBits: 5},
+			want:   DecodedID{ID: 1<<63 | 5<<22 | 3<<17 | 2<<12 | 7, Version: 1, Timestamp: 5, MachineID: 3, Shard: 2, Sequence: 7},
+		},
+		{
+			name:   "Test DecodeID with the spread layout",
+			id:     7<<52 | 5<<10 | 378,
+			layout: Layout{MachineIDBits: 10, Order: OrderSequenceTimestampMachine},
+			want:   DecodedID{ID: 7<<52 | 5<<10 | 378, Timestamp: 5, MachineID: 378, Sequence: 7},
+		},
+		{
+			name:   "Test DecodeID with the spread layout, shard and version bits",
+			id:     1<<63 | 7<<51 | 5<<10 | 3<<5 | 2,
+			layout: Layout{VersionBit: true, MachineIDBits: 5, ShardBits: 5, Order: OrderSequenceTimestampMachine},
+			want:   DecodedID{ID: 1<<63 | 7<<51 | 5<<10 | 3<<5 | 2, Version: 1, Timestamp: 5, MachineID: 3, Shard: 2, Sequence: 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeID(tt.id, tt.layout); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDecodeWithEpoch tests DecodeWithEpoch with IDs of Twitter, Discord and Instagram
+func TestDecodeWithEpoch(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     ID
+		epoch  time.Time
+		layout Layout
+		want   Components
+	}{
+		{
+			name:   "first tweet",
+			id:     1541815603606036480,
+			epoch:  TwitterEpoch,
+			layout: Layout{VersionBit: true, MachineIDBits: 10},
+			want: Components{
+				DecodedID: DecodedID{ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378},
+				Time:      time.Date(2022, 6, 28, 16, 7, 40, 105000000, time.UTC),
+			},
+		},
+		{
+			name:   "discord documentation",
+			id:     175928847299117063,
+			epoch:  DiscordEpoch,
+			layout: Layout{MachineIDBits: 10},
+			want: Components{
+				DecodedID: DecodedID{ID: 175928847299117063, Timestamp: 41944705796, MachineID: 32, Sequence: 7},
+				Time:      time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC),
+			},
+		},
+		{
+			name:   "instagram",
+			id:     1387263000<<23 | 5001<<10 | 7,
+			epoch:  InstagramEpoch,
+			layout: Layout{TimestampBits: 40, MachineIDBits: 13},
+			want: Components{
+				DecodedID: DecodedID{ID: 1387263000<<23 | 5001<<10 | 7, Timestamp: 1387263000, MachineID: 5001, Sequence: 7},
+				Time:      time.UnixMilli(1314220021721 + 1387263000).UTC(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeWithEpoch(tt.id, tt.epoch, tt.layout)
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGenerator_Components tests that Components decodes the fields and the time with the generator epoch
+func TestGenerator_Components(t *testing.T) {
+	g, err := NewGenerator(378, WithEpoch(TwitterEpoch), WithVersionBit(0))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	got := g.Components(1541815603606036480)
+	if got.MachineID != 378 || got.Timestamp != 367597485448 || got.Sequence != 0 {
+		t.Errorf("expected machine ID 378, timestamp 367597485448 and sequence 0, got %v", got)
+	}
+	if want := g.Time(1541815603606036480); !got.Time.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got.Time)
+	}
+	if got.DecodedID != g.DecodeID(1541815603606036480) {
+		t.Errorf("expected %v, got %v", g.DecodeID(1541815603606036480), got.DecodedID)
+	}
+}
+
+// TestGenerator_DecodeIDs tests that DecodeIDs decodes like Components in the order of the input
+func TestGenerator_DecodeIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "default"},
+		{name: "version, shard and nonce", opts: []Option{WithVersionBit(1), WithMachineIDBits(8), WithShardBits(4),
+			WithInstanceNonceBits(2)}},
+		{name: "spread", opts: []Option{WithSpreadLayout(), WithShardBits(2)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator(5, append([]Option{WithDriftNoWait(time.Second)}, tt.opts...)...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			ids := make([]ID, 100)
+			for i := range ids {
+				if ids[i], err = g.NextIDForShard(uint64(i) & g.shardMask); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+			// Reverse the IDs to check that the order of the input is kept
+			for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+				ids[i], ids[j] = ids[j], ids[i]
+			}
+			got := g.DecodeIDs(ids)
+			if len(got) != len(ids) {
+				t.Errorf("expected %v components, got %v", len(ids), len(got))
+				return
+			}
+			for i, id := range ids {
+				if want := g.Components(id); got[i] != want {
+					t.Errorf("expected %v, got %v", want, got[i])
+					return
+				}
+			}
+		})
+	}
+
+	g, err := NewGenerator(5)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := g.DecodeIDs(nil); len(got) != 0 {
+		t.Errorf("expected no components, got %v", got)
+	}
+}
+
+// TestComponents_String tests the String method of Components
+func TestComponents_String(t *testing.T) {
+	got := DecodeWithEpoch(1541815603606036480, TwitterEpoch, Layout{VersionBit: true, MachineIDBits: 10}).String()
+	want := "ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378, Sequence: 0, Time: 2022-06-28T16:07:40.105Z"
+	if got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestGenerator_Time tests the Generator Time and Timestamp methods with the test vector of the first tweet
+func TestGenerator_Time(t *testing.T) {
+	g, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	id := ID(1541815603606036480)
+	if got := g.Timestamp(id); got != 1656432460105-1288834974657 {
+		t.Errorf("expected %v, got %v", 1656432460105-1288834974657, got)
+	}
+	got := g.Time(id)
+	if want := time.UnixMilli(1656432460105); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got.Location() != time.UTC {
+		t.Errorf("expected UTC, got %v", got.Location())
+	}
+	if got := g.Time(0); !got.Equal(time.UnixMilli(1288834974657)) {
+		t.Errorf("expected the epoch, got %v", got)
+	}
+}
+
+// TestGenerator_Timestamps tests the Generator Timestamps method
+func TestGenerator_Timestamps(t *testing.T) {
+	g, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	got := g.Timestamps([]ID{1541815603606036480, 0})
+	want := []time.Time{time.UnixMilli(1656432460105), time.UnixMilli(1288834974657)}
+	if len(got) != len(want) {
+		t.Errorf("expected %v, got %v", want, got)
+		return
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("expected %v, got %v", want[i], got[i])
+		}
+	}
+}
+
+// TestGenerator_Timestamps_Time tests that every time of Timestamps and DecodeTimestampsInto is the same as Time,
+// including the location
+func TestGenerator_Timestamps_Time(t *testing.T) {
+	g, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ids := []ID{1541815603606036480, 0}
+	want := []time.Time{g.Time(ids[0]), g.Time(ids[1])}
+	if got := g.Timestamps(ids); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	out := make([]time.Time, len(ids))
+	if err = g.DecodeTimestampsInto(ids, out); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("expected %v, got %v", want, out)
+	}
+}
+
+// TestGenerator_DecodeTimestampsInto tests that DecodeTimestampsInto fills the caller buffer without allocating
+func TestGenerator_DecodeTimestampsInto(t *testing.T) {
+	g, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ids := []ID{1541815603606036480, 0}
+	out := make([]time.Time, 3)
+	if err := g.DecodeTimestampsInto(ids, out); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	want := []time.Time{time.UnixMilli(1656432460105), time.UnixMilli(1288834974657), {}}
+	for i := range want {
+		if !out[i].Equal(want[i]) {
+			t.Errorf("expected %v, got %v", want[i], out[i])
+		}
+	}
+
+	if err := g.DecodeTimestampsInto(ids, out[:1]); !errors.Is(err, ErrBufferTooShort) {
+		t.Errorf("expected %v, got %v", ErrBufferTooShort, err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = g.DecodeTimestampsInto(ids, out)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+// BenchmarkGenerator_DecodeTimestampsInto benchmarks DecodeTimestampsInto with 1000 IDs per call
+func BenchmarkGenerator_DecodeTimestampsInto(b *testing.B) {
+	g, _ := NewGenerator(378)
+	ids := make([]ID, 1000)
+	for i := range ids {
+		ids[i] = ID(1541815603606036480 + uint64(i)<<22)
+	}
+	out := make([]time.Time, len(ids))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = g.DecodeTimestampsInto(ids, out)
+	}
+}
+
+// TestGenerator_SpanMilliseconds tests that SpanMilliseconds counts the distinct milliseconds of unsorted IDs
+func TestGenerator_SpanMilliseconds(t *testing.T) {
+	g, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	tests := []struct {
+		name string
+		ids  []ID
+		want uint64
+	}{
+		{name: "empty", ids: nil, want: 0},
+		{name: "one millisecond", ids: []ID{5<<22 | 378<<12, 5<<22 | 378<<12 | 1, 5<<22 | 1<<12 | 4095}, want: 1},
+		{name: "unsorted", ids: []ID{7 << 22, 5 << 22, 7<<22 | 1, 6 << 22, 5<<22 | 1}, want: 3},
+		{name: "duplicates", ids: []ID{1541815603606036480, 1541815603606036480}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.SpanMilliseconds(tt.ids); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGenerator_NextIDDecoded tests that NextIDDecoded returns the same components as DecodeID
+func TestGenerator_NextIDDecoded(t *testing.T) {
+	for _, opts := range [][]Option{{}, {WithVersionBit(1)}, {WithInstanceNonceBits(4)}, {WithSpreadLayout()}} {
+		g, err := NewGenerator(378, append(opts, WithEpoch(time.UnixMilli(0)))...)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		g.SetTimeFunc(func() uint64 {
+			return 367597485448
+		})
+		for i := 0; i < 3; i++ {
+			id, decoded, err := g.NextIDDecoded()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if want := g.DecodeID(id); decoded != want {
+				t.Errorf("got %v, want %v", decoded, want)
+			}
+		}
+	}
+}
+
+// TestGenerator_Bucket tests the Generator Bucket method with the Twitter test vector
+func TestGenerator_Bucket(t *testing.T) {
+	g, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// The first Tweet was created 367597485448ms after the Twitter epoch
+	id := ID(1541815603606036480)
+	tests := []struct {
+		window  time.Duration
+		want    int64
+		wantErr error
+	}{
+		{window: time.Millisecond, want: 367597485448},
+		{window: time.Hour, want: 102110},
+		{window: 24 * time.Hour, want: 4254},
+		{window: 0, wantErr: ErrInvalidWindow},
+		{window: -time.Hour, wantErr: ErrInvalidWindow},
+		{window: 999 * time.Microsecond, wantErr: ErrInvalidWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window.String(), func(t *testing.T) {
+			got, err := g.Bucket(id, tt.window)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// With a time unit of a microsecond a window of a microsecond is valid
+	g, err = NewGenerator(378, WithEpoch(time.Now().Add(-time.Hour)), WithMicrosecondResolution())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got, err := g.Bucket(ID(5<<22), time.Microsecond); err != nil || got != 5 {
+		t.Errorf("expected 5, got %v, %v", got, err)
+	}
+}
+
+// TestGenerator_DecodeRaw tests that DecodeRaw does not depend on the epoch
+func TestGenerator_DecodeRaw(t *testing.T) {
+	for _, epoch := range []int64{0, 1288834974657} {
+		g, err := NewGenerator(1, WithEpochMillis(epoch))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		want := RawDecoded{ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378}
+		if got := g.DecodeRaw(1541815603606036480); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidDeltaEncoding is returned when a delta encoded blob is truncated, too long or has an invalid varint
+	ErrInvalidDeltaEncoding = errors.New("invalid delta encoding")
+)
+
+// EncodeIDsDelta encodes IDs compactly as the number of IDs, the first ID and the differences between consecutive IDs
+// All numbers are varints, the differences are signed and computed modulo 2^64, so any slice round-trips exactly
+// Sequential IDs of one generator differ by a few bits, which makes their differences a few bytes each
+func EncodeIDsDelta(ids []ID) []byte {
+	b := make([]byte, 0, binary.MaxVarintLen64+len(ids)*4)
+	b = binary.AppendUvarint(b, uint64(len(ids)))
+	var previous ID
+	for i, id := range ids {
+		if i == 0 {
+			b = binary.AppendUvarint(b, uint64(id))
+		} else {
+			b = binary.AppendVarint(b, int64(id-previous))
+		}
+		previous = id
+	}
+	return b
+}
+
+// DecodeIDsDelta decodes IDs encoded with EncodeIDsDelta
+// Returns ErrInvalidDeltaEncoding wrapped with the position if the blob is truncated, has trailing bytes or an invalid
+// varint
+func DecodeIDsDelta(b []byte) ([]ID, error) {
+	count, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: invalid count", ErrInvalidDeltaEncoding)
+	}
+	// Every ID takes at least one byte, which bounds the allocation for corrupt counts
+	if count > uint64(len(b)-n) {
+		return nil, fmt.Errorf("%w: %d IDs do not fit in %d bytes", ErrInvalidDeltaEncoding, count, len(b)-n)
+	}
+	ids := make([]ID, count)
+	offset := n
+	for i := range ids {
+		if i == 0 {
+			first, n := binary.Uvarint(b[offset:])
+			if n <= 0 {
+				return nil, fmt.Errorf("%w: invalid ID at byte %d", ErrInvalidDeltaEncoding, offset)
+			}
+			ids[i] = ID(first)
+			offset += n
+			continue
+		}
+		delta, n := binary.Varint(b[offset:])
+		if n <= 0 {
+			return nil, fmt.Errorf("%w: invalid delta at byte %d", ErrInvalidDeltaEncoding, offset)
+		}
+		ids[i] = ids[i-1] + ID(delta)
+		offset += n
+	}
+	if offset != len(b) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidDeltaEncoding, len(b)-offset)
+	}
+	return ids, nil
+}
@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestEncodeIDsDelta tests that delta encoded IDs decode to the exact slice
+func TestEncodeIDsDelta(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	sequential := make([]ID, 1000)
+	for i := range sequential {
+		if sequential[i], err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	tests := []struct {
+		name string
+		ids  []ID
+	}{
+		{name: "empty", ids: []ID{}},
+		{name: "single", ids: []ID{1541815603606036480}},
+		{name: "sequential", ids: sequential},
+		{name: "unordered and extreme", ids: []ID{math.MaxUint64, 0, math.MaxUint64, 1, 1 << 63}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeIDsDelta(EncodeIDsDelta(tt.ids))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.ids) {
+				t.Errorf("expected %v, got %v", tt.ids, got)
+			}
+		})
+	}
+	// Sequential IDs differ by 1, which fits in a single byte
+	if got := len(EncodeIDsDelta(sequential)); got > 2+binary.MaxVarintLen64+len(sequential) {
+		t.Errorf("expected about one byte per sequential ID, got %v bytes", got)
+	}
+}
+
+// TestDecodeIDsDelta_Errors tests that DecodeIDsDelta rejects corrupt blobs
+func TestDecodeIDsDelta_Errors(t *testing.T) {
+	valid := EncodeIDsDelta([]ID{1541815603606036480, 1541815603606036481})
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{name: "empty", b: nil},
+		{name: "truncated", b: valid[:len(valid)-1]},
+		{name: "trailing bytes", b: append(append([]byte{}, valid...), 0)},
+		{name: "count too large", b: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 1}},
+		{name: "invalid varint", b: []byte{2, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeIDsDelta(tt.b); !errors.Is(err, ErrInvalidDeltaEncoding) {
+				t.Errorf("expected ErrInvalidDeltaEncoding, got %v", err)
+			}
+		})
+	}
+}
+
+// FuzzDecodeIDsDelta tests that DecodeIDsDelta does not panic and that decoded IDs encode to the same blob
+func FuzzDecodeIDsDelta(f *testing.F) {
+	f.Add(EncodeIDsDelta([]ID{1541815603606036480, 1541815603606036481}))
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		ids, err := DecodeIDsDelta(b)
+		if err != nil {
+			return
+		}
+		again, err := DecodeIDsDelta(EncodeIDsDelta(ids))
+		if err != nil || !reflect.DeepEqual(again, ids) {
+			t.Errorf("expected %v, got %v and %v", ids, again, err)
+		}
+	})
+}
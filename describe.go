@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// describeNames are the short field names used by Describe
+var describeNames = map[string]string{
+	"version":    "version",
+	"unused":     "unused",
+	"timestamp":  "time",
+	"machine ID": "machine",
+	"shard":      "shard",
+	"nonce":      "nonce",
+	"sequence":   "seq",
+}
+
+// Describe returns a one-line summary of the configuration of the generator for startup logs, for example:
+// snowflake(epoch=2024-02-29T23:00:00Z,bits=time:42/machine:10/seq:12,unit=1ms,machine=378)
+// The epoch is in UTC, the bits are listed from the most to the least significant field and fields without bits are
+// omitted. The unit is logical when WithLogicalClock is used
+func (g *Generator) Describe() string {
+	fields := g.layout.fields()
+	bits := make([]string, len(fields))
+	for i, f := range fields {
+		bits[i] = fmt.Sprintf("%s:%d", describeNames[f.name], f.bits)
+	}
+	unit := g.unit.String()
+	if g.logical {
+		unit = "logical"
+	}
+	return fmt.Sprintf("snowflake(epoch=%s,bits=%s,unit=%s,machine=%d)",
+		g.Epoch().UTC().Format("2006-01-02T15:04:05.999Z07:00"), strings.Join(bits, "/"), unit, g.MachineID())
+}
@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGenerator_Describe tests the one-line configuration summary
+func TestGenerator_Describe(t *testing.T) {
+	tests := []struct {
+		name      string
+		machineID uint64
+		opts      []Option
+		expected  string
+	}{
+		{
+			name:      "default",
+			machineID: 378,
+			expected:  "snowflake(epoch=2024-02-29T23:00:00Z,bits=time:42/machine:10/seq:12,unit=1ms,machine=378)",
+		},
+		{
+			name:      "twitter epoch",
+			machineID: 1,
+			opts:      []Option{WithEpoch(time.UnixMilli(1288834974657))},
+			expected:  "snowflake(epoch=2010-11-04T01:42:54.657Z,bits=time:42/machine:10/seq:12,unit=1ms,machine=1)",
+		},
+		{
+			name:      "all fields",
+			machineID: 3,
+			opts:      []Option{WithVersionBit(0), WithMachineIDBits(8), WithShardBits(2), WithInstanceNonceBits(4)},
+			expected: "snowflake(epoch=2024-02-29T23:00:00Z," +
+				"bits=version:1/time:41/machine:8/shard:2/nonce:4/seq:8,unit=1ms,machine=3)",
+		},
+		{
+			name:      "logical clock",
+			machineID: 7,
+			opts:      []Option{WithLogicalClock()},
+			expected:  "snowflake(epoch=2024-02-29T23:00:00Z,bits=time:42/machine:10/seq:12,unit=logical,machine=7)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(tt.machineID, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.Describe(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
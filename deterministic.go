@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// DeterministicID returns an ID derived from the seed instead of the time, so the same seed always yields the same ID
+// The ID is the first 8 bytes of the SHA-256 hash of the seed read as a big-endian number, which fills the
+// timestamp, machine ID, shard, nonce and sequence fields of the layout with hash bits. The version bit is cleared
+// when the layout reserves it
+// These IDs are not monotonic and their timestamp is meaningless, use them for idempotency keys and deduplication only
+// IDs from different seeds collide with a probability of about n²/2^(b+1) for n seeds and b hash bits
+func DeterministicID(seed []byte, layout Layout) ID {
+	sum := sha256.Sum256(seed)
+	id := binary.BigEndian.Uint64(sum[:8])
+	if layout.VersionBit {
+		id &^= 1 << 63
+	}
+	return ID(id)
+}
+
+// GenerateSequence returns n consecutive IDs as a generator with the given machine ID and layout and the default epoch
+// would generate them from startTime on, for fixtures and golden files
+// The output is fully deterministic: it only depends on the arguments, there is no clock, sleeping, randomness or
+// concurrency involved. The first ID has sequence zero at startTime, every next ID increments the sequence and the
+// timestamp advances by one millisecond when the sequence is exhausted. The nonce, shard and version are zero
+// It panics if startTime is before the default epoch, the machine ID does not fit in the layout or the IDs run past
+// the last timestamp of the layout, which are programming errors in a fixture
+func GenerateSequence(n int, startTime time.Time, machineID uint64, layout Layout) []ID {
+	timestamp := startTime.UnixMilli() - defaultEpoch
+	if timestamp < 0 {
+		panic(ErrTimeBeforeEpoch)
+	}
+	components := DecodedID{Timestamp: uint64(timestamp), MachineID: machineID}
+	ids := make([]ID, n)
+	for i := range ids {
+		id, err := ComposeID(components, layout)
+		if err != nil {
+			panic(err)
+		}
+		ids[i] = id
+		components.Sequence++
+		if components.Sequence > layout.sequenceMask() {
+			components.Timestamp++
+			components.Sequence = 0
+		}
+	}
+	return ids
+}
@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDeterministicID tests that DeterministicID is reproducible and clears the version bit
+func TestDeterministicID(t *testing.T) {
+	a := DeterministicID([]byte("request-1"), DefaultLayout())
+	if b := DeterministicID([]byte("request-1"), DefaultLayout()); a != b {
+		t.Errorf("expected %v, got %v", uint64(a), uint64(b))
+	}
+	if c := DeterministicID([]byte("request-2"), DefaultLayout()); a == c {
+		t.Errorf("expected different IDs for different seeds, got %v", uint64(c))
+	}
+	for i := 0; i < 100; i++ {
+		id := DeterministicID([]byte(fmt.Sprint(i)), Layout{VersionBit: true, MachineIDBits: 10})
+		if DecodeID(id, Layout{VersionBit: true, MachineIDBits: 10}).Version != 0 {
+			t.Errorf("expected version 0, got %v", uint64(id))
+		}
+	}
+}
+
+// ExampleDeterministicID is an example of a deterministic ID for an idempotency key
+func ExampleDeterministicID() {
+	fmt.Println(uint64(DeterministicID([]byte("hello"), DefaultLayout())))
+	// Output:
+	// 3238736544897475342
+}
+
+// TestGenerateSequence tests that GenerateSequence fills the sequence before advancing the timestamp
+func TestGenerateSequence(t *testing.T) {
+	// With 20 machine ID bits there are four sequence numbers per millisecond
+	layout := Layout{MachineIDBits: 20}
+	start := time.UnixMilli(1709247600000 + 100)
+	ids := GenerateSequence(6, start, 3, layout)
+	want := []DecodedID{
+		{Timestamp: 100, MachineID: 3, Sequence: 0},
+		{Timestamp: 100, MachineID: 3, Sequence: 1},
+		{Timestamp: 100, MachineID: 3, Sequence: 2},
+		{Timestamp: 100, MachineID: 3, Sequence: 3},
+		{Timestamp: 101, MachineID: 3, Sequence: 0},
+		{Timestamp: 101, MachineID: 3, Sequence: 1},
+	}
+	if len(ids) != len(want) {
+		t.Errorf("expected %d IDs, got %d", len(want), len(ids))
+		return
+	}
+	for i, id := range ids {
+		want[i].ID = uint64(id)
+		if got := DecodeID(id, layout); got != want[i] {
+			t.Errorf("expected %v, got %v", want[i], got)
+			return
+		}
+	}
+	if again := GenerateSequence(6, start, 3, layout); !reflect.DeepEqual(again, ids) {
+		t.Errorf("expected %v, got %v", ids, again)
+	}
+}
+
+// TestGenerateSequence_Panics tests that invalid fixtures panic
+func TestGenerateSequence_Panics(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     time.Time
+		machineID uint64
+		expected  error
+	}{
+		{name: "before epoch", start: time.UnixMilli(1709247600000 - 1), expected: ErrTimeBeforeEpoch},
+		{
+			name:      "machine ID too large",
+			start:     time.UnixMilli(1709247600000),
+			machineID: 1024,
+			expected:  ErrMachineIDTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, tt.expected) {
+					t.Errorf("expected a panic with %v, got %v", tt.expected, err)
+				}
+			}()
+			GenerateSequence(1, tt.start, tt.machineID, DefaultLayout())
+		})
+	}
+}
+
+// ExampleGenerateSequence is an example of fixture IDs for a golden test
+func ExampleGenerateSequence() {
+	for _, id := range GenerateSequence(3, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 1, DefaultLayout()) {
+		fmt.Println(uint64(id))
+	}
+	// Output:
+	// 33354783129604096
+	// 33354783129604097
+	// 33354783129604098
+}
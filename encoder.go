@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"github.com/crosscode-nl/snowflake/internal/codecs/basex"
+	"io"
+	"sync"
+)
+
+const (
+	// Base62Alphabet is the alphabet of the base62 encoding, digits followed by upper and lower case letters
+	Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// Base58Alphabet is the Bitcoin base58 alphabet, which leaves out the look-alike characters 0, O, I and l
+	Base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// bufferPool holds the buffers of EncodeTo
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 4096)
+		return &b
+	},
+}
+
+var (
+	// ErrInvalidAlphabet is returned when an alphabet is too short, too long, contains duplicate or non-ASCII characters
+	ErrInvalidAlphabet = errors.New("invalid alphabet")
+)
+
+// Encoder encodes snowflake IDs as variable length strings in the base of its alphabet
+// The ID is converted with the most significant digit first and without leading zeros, like strconv.FormatUint
+type Encoder struct {
+	alphabet string
+	lookup   basex.Lookup
+}
+
+// NewEncoder creates an encoder for the given alphabet, the length of the alphabet is the base of the encoding
+// Returns an error if the alphabet has less than 2 or more than 128 characters, or contains duplicate or non-ASCII
+// characters
+func NewEncoder(alphabet string) (*Encoder, error) {
+	if len(alphabet) < 2 || len(alphabet) > 128 {
+		return nil, fmt.Errorf("%w: length %d is not between 2 and 128", ErrInvalidAlphabet, len(alphabet))
+	}
+	var seen [128]bool
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c >= 128 {
+			return nil, fmt.Errorf("%w: non-ASCII character at position %d", ErrInvalidAlphabet, i)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("%w: duplicate character %q", ErrInvalidAlphabet, c)
+		}
+		seen[c] = true
+	}
+	return &Encoder{alphabet: alphabet, lookup: basex.NewLookup(alphabet)}, nil
+}
+
+// Encode returns the snowflake ID encoded in the alphabet of the encoder
+func (e *Encoder) Encode(id ID) string {
+	var b [64]byte
+	return string(basex.Append(b[:0], uint64(id), e.alphabet))
+}
+
+// Append appends the snowflake ID encoded in the alphabet of the encoder to dst and returns the extended buffer
+// Append does not allocate when dst has enough capacity
+func (e *Encoder) Append(dst []byte, id ID) []byte {
+	return basex.Append(dst, uint64(id), e.alphabet)
+}
+
+// EncodeTo writes the snowflake IDs encoded in the alphabet of the encoder to w, each followed by sep
+// The IDs are encoded into a pooled buffer that is flushed to w when it is full, so encoding does not allocate
+// Returns the first error of w
+func (e *Encoder) EncodeTo(w io.Writer, ids []ID, sep byte) error {
+	bp := bufferPool.Get().(*[]byte)
+	defer bufferPool.Put(bp)
+	b := (*bp)[:0]
+	for _, id := range ids {
+		if cap(b)-len(b) < 65 {
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+			b = b[:0]
+		}
+		b = append(e.Append(b, id), sep)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// Decode returns the snowflake ID from a string encoded in the alphabet of the encoder
+// Returns an error if the string is empty, contains characters outside the alphabet or does not fit in 64 bits
+func (e *Encoder) Decode(s string) (ID, error) {
+	n, ok := basex.Decode(s, uint64(len(e.alphabet)), &e.lookup)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidID, s)
+	}
+	return ID(n), nil
+}
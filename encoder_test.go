@@ -0,0 +1,154 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestNewEncoder_Errors tests that NewEncoder rejects invalid alphabets
+func TestNewEncoder_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet string
+	}{
+		{name: "empty", alphabet: ""},
+		{name: "too short", alphabet: "0"},
+		{name: "duplicate", alphabet: "0123456789abcdefa"},
+		{name: "non-ASCII", alphabet: "01é"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEncoder(tt.alphabet); !errors.Is(err, ErrInvalidAlphabet) {
+				t.Errorf("expected ErrInvalidAlphabet, got %v", err)
+			}
+		})
+	}
+}
+
+// TestEncoder tests that IDs round-trip through encoders with different alphabets
+func TestEncoder(t *testing.T) {
+	alphabets := []string{
+		"01",
+		"0123456789",
+		"123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz",
+		"0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+	}
+	for _, alphabet := range alphabets {
+		t.Run(fmt.Sprintf("base%d", len(alphabet)), func(t *testing.T) {
+			encoder, err := NewEncoder(alphabet)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			for _, id := range []ID{0, 1, 57, 1541815603606036480, math.MaxUint64} {
+				got, err := encoder.Decode(encoder.Encode(id))
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				if got != id {
+					t.Errorf("expected %v, got %v", uint64(id), uint64(got))
+				}
+			}
+			if _, err = encoder.Decode("!"); !errors.Is(err, ErrInvalidID) {
+				t.Errorf("expected ErrInvalidID, got %v", err)
+			}
+		})
+	}
+}
+
+// ExampleNewEncoder is an example of an Encoder using the Bitcoin base58 alphabet
+func ExampleNewEncoder() {
+	encoder, err := NewEncoder("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+	if err != nil {
+		panic(err)
+	}
+	s := encoder.Encode(1541815603606036480)
+	fmt.Println(s)
+	id, _ := encoder.Decode(s)
+	fmt.Println(uint64(id))
+	// Output:
+	// 4aaW4SzAyQK
+	// 1541815603606036480
+}
+
+// TestEncoder_Append tests that Append appends the same encoding as Encode
+func TestEncoder_Append(t *testing.T) {
+	encoder, err := NewEncoder(Base62Alphabet)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	b := []byte("id=")
+	for _, id := range []ID{0, 1, 1541815603606036480, math.MaxUint64} {
+		if got, want := string(encoder.Append(b, id)), "id="+encoder.Encode(id); got != want {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestEncoder_EncodeTo tests that EncodeTo writes every ID followed by the separator
+func TestEncoder_EncodeTo(t *testing.T) {
+	encoder, err := NewEncoder(Base62Alphabet)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// Enough IDs to flush the pooled buffer several times
+	ids := make([]ID, 1000)
+	var want strings.Builder
+	for i := range ids {
+		ids[i] = ID(math.MaxUint64 - uint64(i))
+		want.WriteString(encoder.Encode(ids[i]) + "\n")
+	}
+	var got bytes.Buffer
+	if err = encoder.EncodeTo(&got, ids, '\n'); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got.String() != want.String() {
+		t.Errorf("expected %v, got %v", want.String(), got.String())
+	}
+}
+
+// encoded keeps the result of BenchmarkEncoder_Encode alive, so the string is not optimized away
+var encoded string
+
+// BenchmarkEncoder_Encode benchmarks the Encode method of the Encoder, which allocates a string per ID
+func BenchmarkEncoder_Encode(b *testing.B) {
+	encoder, _ := NewEncoder(Base62Alphabet)
+	id := ID(1541815603606036480)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encoded = encoder.Encode(id)
+	}
+}
+
+// BenchmarkEncoder_Append benchmarks the Append method of the Encoder, which reuses the buffer
+func BenchmarkEncoder_Append(b *testing.B) {
+	encoder, _ := NewEncoder(Base62Alphabet)
+	id := ID(1541815603606036480)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = encoder.Append(buf[:0], id)
+	}
+}
+
+// BenchmarkEncoder_EncodeTo benchmarks the EncodeTo method of the Encoder with 1000 IDs per call
+func BenchmarkEncoder_EncodeTo(b *testing.B) {
+	encoder, _ := NewEncoder(Base62Alphabet)
+	ids := make([]ID, 1000)
+	for i := range ids {
+		ids[i] = ID(1541815603606036480 + uint64(i))
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = encoder.EncodeTo(io.Discard, ids, '\n')
+	}
+}
@@ -0,0 +1,29 @@
+package snowflake
+
+import (
+	"github.com/crosscode-nl/snowflake/internal/codecs/basex"
+	"strconv"
+)
+
+var (
+	// base62 encodes IDs with Base62Alphabet
+	base62 = &Encoder{alphabet: Base62Alphabet, lookup: basex.NewLookup(Base62Alphabet)}
+	// base58 encodes IDs with Base58Alphabet
+	base58 = &Encoder{alphabet: Base58Alphabet, lookup: basex.NewLookup(Base58Alphabet)}
+)
+
+// AllEncodings returns the ID in every supported encoding by name, which is useful when debugging
+// The names are decimal, lowerhex, upperhex, base32, base64, base64url, influx64, base62 and base58
+func AllEncodings(id ID) map[string]string {
+	return map[string]string{
+		"decimal":   strconv.FormatUint(uint64(id), 10),
+		"lowerhex":  id.LowerHexString(),
+		"upperhex":  id.UpperHexString(),
+		"base32":    id.Base32(),
+		"base64":    id.Base64String(),
+		"base64url": id.Base64URL(),
+		"influx64":  id.Influx64String(),
+		"base62":    id.Base62(),
+		"base58":    base58.Encode(id),
+	}
+}
@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAllEncodings tests that every encoding of AllEncodings parses back to the same ID
+func TestAllEncodings(t *testing.T) {
+	parse := map[string]func(string) (ID, error){
+		"decimal": ParseID,
+		"lowerhex": func(s string) (ID, error) {
+			return IDFromLowerHexString(s), nil
+		},
+		"upperhex": func(s string) (ID, error) {
+			return IDFromUpperHexString(s), nil
+		},
+		"base32": ParseBase32,
+		"base64": func(s string) (ID, error) {
+			return IDFromBase64String(s), nil
+		},
+		"base64url": ParseBase64URL,
+		"influx64": func(s string) (ID, error) {
+			return IDFromInflux64String(s), nil
+		},
+		"base62": base62.Decode,
+		"base58": base58.Decode,
+	}
+	for _, id := range []ID{0, 1541815603606036480, math.MaxUint64} {
+		encodings := AllEncodings(id)
+		if len(encodings) != len(parse) {
+			t.Errorf("expected %d encodings, got %v", len(parse), encodings)
+		}
+		for name, s := range encodings {
+			p, ok := parse[name]
+			if !ok {
+				t.Errorf("expected a parser for %s", name)
+				continue
+			}
+			got, err := p(s)
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", name, err)
+				continue
+			}
+			if got != id {
+				t.Errorf("%s: expected %v, got %v", name, uint64(id), uint64(got))
+			}
+		}
+	}
+}
+
+// TestAllEncodings_TwitterVector tests the encodings of the Twitter test vector
+func TestAllEncodings_TwitterVector(t *testing.T) {
+	encodings := AllEncodings(1541815603606036480)
+	want := map[string]string{
+		"decimal":   "1541815603606036480",
+		"lowerhex":  "1565a11f6217a000",
+		"upperhex":  "1565A11F6217A000",
+		"base32":    "CVS2CH3CC6QAA",
+		"base64url": "FWWhH2IXoAA",
+		"base58":    "4aaW4SzAyQK",
+	}
+	for name, s := range want {
+		if encodings[name] != s {
+			t.Errorf("%s: expected %v, got %v", name, s, encodings[name])
+		}
+	}
+}
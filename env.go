@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrMissingEnv is returned when a required environment variable is not set
+	ErrMissingEnv = errors.New("missing environment variable")
+	// ErrInvalidEnv is returned when an environment variable has an invalid value
+	ErrInvalidEnv = errors.New("invalid environment variable")
+)
+
+// NewGeneratorFromEnv creates a new snowflake ID generator from environment variables with the given prefix, for
+// example SNOWFLAKE_MACHINE_ID for the prefix SNOWFLAKE. The variables are:
+//   - MACHINE_ID: the machine ID, required
+//   - MACHINE_ID_BITS: the number of machine ID bits, see WithMachineIDBits
+//   - SHARD_BITS: the number of shard bits, see WithShardBits
+//   - NONCE_BITS: the number of instance nonce bits, see WithInstanceNonceBits
+//   - VERSION_BIT: the version bit, 0 or 1, see WithVersionBit
+//   - EPOCH_MILLIS: the epoch in Unix milliseconds, see WithEpochMillis
+//   - TIME_UNIT: the time unit of the timestamp as a duration such as 1us, see WithTimeUnit
+//   - DRIFT: the drift as a duration such as 1s, see WithDrift
+//   - STRICT: whether strict mode is enabled, as a boolean such as true, see WithStrict
+//
+// Variables that are not set keep the defaults of NewGenerator, opts are applied after the variables
+// Returns ErrMissingEnv or ErrInvalidEnv wrapped with the name of the variable, or the error of NewGenerator
+func NewGeneratorFromEnv(prefix string, opts ...Option) (*Generator, error) {
+	env := envReader{prefix: prefix}
+	machineID, ok := env.uint("MACHINE_ID")
+	if !ok && env.err == nil {
+		env.err = fmt.Errorf("%w: %s", ErrMissingEnv, env.name("MACHINE_ID"))
+	}
+	var envOpts []Option
+	if bits, ok := env.uint("MACHINE_ID_BITS"); ok {
+		envOpts = append(envOpts, WithMachineIDBits(bits))
+	}
+	if bits, ok := env.uint("SHARD_BITS"); ok {
+		envOpts = append(envOpts, WithShardBits(bits))
+	}
+	if bits, ok := env.uint("NONCE_BITS"); ok {
+		envOpts = append(envOpts, WithInstanceNonceBits(bits))
+	}
+	if v, ok := env.uint("VERSION_BIT"); ok {
+		envOpts = append(envOpts, WithVersionBit(uint8(v)))
+		if v > 1 {
+			env.fail("VERSION_BIT", "must be 0 or 1")
+		}
+	}
+	if epoch, ok := env.lookup("EPOCH_MILLIS"); ok {
+		ms, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			env.fail("EPOCH_MILLIS", err)
+		}
+		envOpts = append(envOpts, WithEpochMillis(ms))
+	}
+	if unit, ok := env.duration("TIME_UNIT"); ok {
+		envOpts = append(envOpts, WithTimeUnit(unit))
+		if !validTimeUnit(unit) {
+			env.fail("TIME_UNIT", ErrInvalidTimeUnit)
+		}
+	}
+	if strict, ok := env.lookup("STRICT"); ok {
+		enabled, err := strconv.ParseBool(strict)
+		if err != nil {
+			env.fail("STRICT", err)
+		}
+		if enabled {
+			envOpts = append(envOpts, WithStrict())
+		}
+	}
+	if drift, ok := env.duration("DRIFT"); ok {
+		envOpts = append(envOpts, WithDrift(drift))
+	}
+	if env.err != nil {
+		return nil, env.err
+	}
+	return NewGenerator(machineID, append(envOpts, opts...)...)
+}
+
+// envReader reads environment variables with a prefix and keeps the first error
+type envReader struct {
+	prefix string
+	err    error
+}
+
+// name returns the name of the variable with the prefix
+func (e *envReader) name(key string) string {
+	if e.prefix == "" {
+		return key
+	}
+	return e.prefix + "_" + key
+}
+
+// lookup returns the value of the variable and whether it is set
+func (e *envReader) lookup(key string) (string, bool) {
+	return os.LookupEnv(e.name(key))
+}
+
+// fail records that the variable has an invalid value, unless an error is already recorded
+func (e *envReader) fail(key string, reason interface{}) {
+	if e.err == nil {
+		e.err = fmt.Errorf("%w: %s: %v", ErrInvalidEnv, e.name(key), reason)
+	}
+}
+
+// uint returns the variable as an unsigned integer and whether it is set
+func (e *envReader) uint(key string) (uint64, bool) {
+	s, ok := e.lookup(key)
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		e.fail(key, err)
+	}
+	return v, true
+}
+
+// duration returns the variable as a duration and whether it is set
+func (e *envReader) duration(key string) (time.Duration, bool) {
+	s, ok := e.lookup(key)
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		e.fail(key, err)
+	}
+	return d, true
+}
@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewGeneratorFromEnv tests that the settings are read from the environment variables with the prefix
+func TestNewGeneratorFromEnv(t *testing.T) {
+	t.Setenv("SNOWFLAKE_MACHINE_ID", "5")
+	t.Setenv("SNOWFLAKE_MACHINE_ID_BITS", "8")
+	t.Setenv("SNOWFLAKE_SHARD_BITS", "2")
+	t.Setenv("SNOWFLAKE_NONCE_BITS", "1")
+	t.Setenv("SNOWFLAKE_VERSION_BIT", "1")
+	t.Setenv("SNOWFLAKE_EPOCH_MILLIS", "1288834974657")
+	t.Setenv("SNOWFLAKE_TIME_UNIT", "1ms")
+	t.Setenv("SNOWFLAKE_DRIFT", "1ms")
+	t.Setenv("SNOWFLAKE_STRICT", "true")
+
+	g, err := NewGeneratorFromEnv("SNOWFLAKE")
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	want := Layout{VersionBit: true, MachineIDBits: 8, ShardBits: 2, NonceBits: 1}
+	if g.MachineID() != 5 || g.Layout() != want || !g.Epoch().Equal(time.UnixMilli(1288834974657)) {
+		t.Errorf("expected machine ID 5, layout %+v and the Twitter epoch, got %v, %+v and %v", want, g.MachineID(),
+			g.Layout(), g.Epoch())
+	}
+	if g.version != 1 || !g.strict || !g.drift || g.duration != time.Millisecond {
+		t.Errorf("expected version 1, strict mode and 1ms drift, got %v, %v and %v", g.version, g.strict, g.duration)
+	}
+}
+
+// TestNewGeneratorFromEnv_Defaults tests that only the machine ID is required
+func TestNewGeneratorFromEnv_Defaults(t *testing.T) {
+	t.Setenv("MACHINE_ID", "378")
+	g, err := NewGeneratorFromEnv("")
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if g.MachineID() != 378 || g.Layout() != DefaultLayout() || !g.Epoch().Equal(time.UnixMilli(1709247600000)) {
+		t.Errorf("expected machine ID 378 and the defaults, got %v, %+v and %v", g.MachineID(), g.Layout(), g.Epoch())
+	}
+}
+
+// TestNewGeneratorFromEnv_Errors tests that invalid values are reported with the name of the variable
+func TestNewGeneratorFromEnv_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		want     error
+		variable string
+	}{
+		{name: "missing machine ID", env: map[string]string{}, want: ErrMissingEnv, variable: "APP_MACHINE_ID"},
+		{name: "malformed machine ID", env: map[string]string{"APP_MACHINE_ID": "node-1"}, want: ErrInvalidEnv, variable: "APP_MACHINE_ID"},
+		{name: "malformed bits", env: map[string]string{"APP_MACHINE_ID": "1", "APP_MACHINE_ID_BITS": "-1"}, want: ErrInvalidEnv, variable: "APP_MACHINE_ID_BITS"},
+		{name: "invalid version bit", env: map[string]string{"APP_MACHINE_ID": "1", "APP_VERSION_BIT": "2"}, want: ErrInvalidEnv, variable: "APP_VERSION_BIT"},
+		{name: "malformed epoch", env: map[string]string{"APP_MACHINE_ID": "1", "APP_EPOCH_MILLIS": "2024-03-01"}, want: ErrInvalidEnv, variable: "APP_EPOCH_MILLIS"},
+		{name: "unsupported time unit", env: map[string]string{"APP_MACHINE_ID": "1", "APP_TIME_UNIT": "3ms"}, want: ErrInvalidEnv, variable: "APP_TIME_UNIT"},
+		{name: "malformed drift", env: map[string]string{"APP_MACHINE_ID": "1", "APP_DRIFT": "1 second"}, want: ErrInvalidEnv, variable: "APP_DRIFT"},
+		{name: "malformed strict", env: map[string]string{"APP_MACHINE_ID": "1", "APP_STRICT": "yes"}, want: ErrInvalidEnv, variable: "APP_STRICT"},
+		{name: "machine ID too large", env: map[string]string{"APP_MACHINE_ID": "1024"}, want: ErrMachineIDTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			_, err := NewGeneratorFromEnv("APP")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+				return
+			}
+			if !strings.Contains(err.Error(), tt.variable) {
+				t.Errorf("expected the error to name %v, got %v", tt.variable, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Format implements fmt.Formatter for the snowflake ID
+// The supported verbs are:
+//   - %d, %b, %o, %x and %X format the ID as an unsigned integer in base 10, 2, 8 and 16
+//   - %s and %v format the ID as returned by String
+//   - %q formats the ID as returned by String in double quotes
+//   - %+v formats the ID decoded with the default layout, as returned by DecodedID.String
+//
+// Flags, width and precision are applied like they are for an uint64 or a string
+func (id ID) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'd', 'b', 'o', 'x', 'X':
+		fmt.Fprintf(f, formatDirective(f, verb), uint64(id))
+	case 'v':
+		if f.Flag('+') {
+			fmt.Fprint(f, DecodeID(id, DefaultLayout()).String())
+			return
+		}
+		fmt.Fprintf(f, formatDirective(f, 's'), id.String())
+	case 's', 'q':
+		fmt.Fprintf(f, formatDirective(f, verb), id.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(snowflake.ID=%d)", verb, uint64(id))
+	}
+}
+
+// Debug returns the ID with its time, machine ID and sequence decoded with the epoch and layout of the generator, e.g.
+// ID(1541815603606036480){time=2022-06-28T16:07:40Z machine=378 seq=0}
+// The version, shard and nonce are only included when they are not zero
+func Debug(g *Generator, id ID) string {
+	d := g.DecodeID(id)
+	s := fmt.Sprintf("ID(%d){time=%s", d.ID, g.Time(id).Format(time.RFC3339Nano))
+	if d.Version != 0 {
+		s += fmt.Sprintf(" version=%d", d.Version)
+	}
+	s += fmt.Sprintf(" machine=%d", d.MachineID)
+	if d.Shard != 0 {
+		s += fmt.Sprintf(" shard=%d", d.Shard)
+	}
+	if d.Nonce != 0 {
+		s += fmt.Sprintf(" nonce=%d", d.Nonce)
+	}
+	return s + fmt.Sprintf(" seq=%d}", d.Sequence)
+}
+
+// formatDirective rebuilds the formatting directive of the state with the given verb
+func formatDirective(f fmt.State, verb rune) string {
+	directive := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive = append(directive, byte(flag))
+		}
+	}
+	if width, ok := f.Width(); ok {
+		directive = strconv.AppendInt(directive, int64(width), 10)
+	}
+	if precision, ok := f.Precision(); ok {
+		directive = append(directive, '.')
+		directive = strconv.AppendInt(directive, int64(precision), 10)
+	}
+	return string(append(directive, byte(verb)))
+}
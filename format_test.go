@@ -0,0 +1,89 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestID_Format tests the verbs supported by the Format method of the ID type
+func TestID_Format(t *testing.T) {
+	id := ID(1541815603606036480)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "%d", want: "1541815603606036480"},
+		{format: "%22d", want: "   1541815603606036480"},
+		{format: "%x", want: "1565a11f6217a000"},
+		{format: "%#X", want: "0X1565A11F6217A000"},
+		{format: "%020x", want: "00001565a11f6217a000"},
+		{format: "%o", want: "125455021754205720000"},
+		{format: "%s", want: id.String()},
+		{format: "%v", want: id.String()},
+		{format: "%22v", want: "   " + id.String()},
+		{format: "%q", want: `"` + id.String() + `"`},
+		{format: "%+v", want: "ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378, Sequence: 0"},
+		{format: "%t", want: "%!t(snowflake.ID=1541815603606036480)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, id); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestDebug tests the decoded form of Debug with the epoch and layout of the generator
+func TestDebug(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		id   ID
+		want string
+	}{
+		{
+			name: "Twitter epoch",
+			opts: []Option{WithEpoch(TwitterEpoch)},
+			id:   1541815603606036480,
+			want: "ID(1541815603606036480){time=2022-06-28T16:07:40.105Z machine=378 seq=0}",
+		},
+		{
+			name: "whole second",
+			opts: []Option{WithEpoch(time.UnixMilli(0))},
+			id:   ID(1656432460000<<22 | 378<<12 | 5),
+			want: "ID(6947581292709388293){time=2022-06-28T16:07:40Z machine=378 seq=5}",
+		},
+		{
+			name: "version and shard",
+			opts: []Option{WithEpoch(time.UnixMilli(0)), WithVersionBit(1), WithShardBits(2)},
+			id:   ID(1<<63 | 1656432460000<<22 | 378<<12 | 3<<10 | 7),
+			want: "ID(16170953329564167175){time=2022-06-28T16:07:40Z version=1 machine=378 shard=3 seq=7}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(378, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := Debug(generator, tt.id); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// ExampleID_Format is an example of formatting an ID with fmt verbs
+func ExampleID_Format() {
+	id := ID(1541815603606036480)
+	fmt.Printf("%d\n", id)
+	fmt.Printf("%x\n", id)
+	fmt.Printf("%+v\n", id)
+	// Output:
+	// 1541815603606036480
+	// 1565a11f6217a000
+	// ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378, Sequence: 0
+}
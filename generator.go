@@ -3,6 +3,9 @@ package snowflake
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -10,38 +13,59 @@ import (
 var (
 	// ErrMachineIDTooLarge is returned when the machine ID is too large for the number of bits
 	ErrMachineIDTooLarge = errors.New("machine ID is too large")
+	// ErrInvalidMachineIDBits is returned when the number of bits for the machine ID is invalid, it is wrapped by
+	// ErrMachineBitsTooSmall and ErrMachineBitsTooLarge
+	ErrInvalidMachineIDBits = errors.New("invalid machine ID bits")
 	// ErrMachineBitsTooSmall is returned when the number of bits for the machine ID is too small
-	ErrMachineBitsTooSmall = errors.New("machine ID bits is too small")
+	ErrMachineBitsTooSmall = fmt.Errorf("%w: machine ID bits is too small", ErrInvalidMachineIDBits)
 	// ErrMachineBitsTooLarge is returned when the number of bits for the machine ID is too large
-	ErrMachineBitsTooLarge = errors.New("machine ID bits is too large")
+	ErrMachineBitsTooLarge = fmt.Errorf("%w: machine ID bits is too large", ErrInvalidMachineIDBits)
 	// ErrOutOfSequence is returned when the sequence number overflows
 	ErrOutOfSequence = errors.New("sequence number overflow")
+	// ErrSequenceExhausted is ErrOutOfSequence, the sequence of the current millisecond is exhausted and NextID can
+	// be tried again in the next millisecond
+	ErrSequenceExhausted = ErrOutOfSequence
 	// ErrTimeBeforeEpoch is returned when the time is before the epoch
 	ErrTimeBeforeEpoch = errors.New("time is before epoch")
+	// ErrEpochInFuture is returned by NewGenerator when the epoch is later than the current time
+	ErrEpochInFuture = errors.New("epoch is in the future")
+	// ErrClockMovedBackwards is returned in strict mode when the clock is behind the last generated ID
+	ErrClockMovedBackwards = errors.New("clock moved backwards")
+	// ErrCanceled is returned when the done channel of BlockingNextIDDone is closed while blocking
+	ErrCanceled = errors.New("canceled")
+	// ErrInvalidFieldOrder is returned when the field order of a layout is not one of the FieldOrder constants
+	ErrInvalidFieldOrder = errors.New("invalid field order")
+	// ErrShardBitsTooLarge is returned when the machine ID and shard bits leave no room for the sequence
+	ErrShardBitsTooLarge = errors.New("shard bits is too large")
+	// ErrShardTooLarge is returned when the shard is too large for the number of bits
+	ErrShardTooLarge = errors.New("shard is too large")
+	// ErrVersionTooLarge is returned when the version does not fit in the version bit
+	ErrVersionTooLarge = errors.New("version is too large")
+	// ErrSequenceTooLarge is returned when the initial sequence is too large for the number of sequence bits
+	ErrSequenceTooLarge = errors.New("sequence is too large")
+	// ErrTimestampBitsTooLarge is returned when the timestamp bits leave no room for the sequence
+	ErrTimestampBitsTooLarge = errors.New("timestamp bits is too large")
+	// ErrInvalidLayout is returned when the bits of WithLayout do not add up to 63
+	ErrInvalidLayout = errors.New("invalid layout")
 )
 
 const (
 	timeShift = 22
+	// defaultEpoch is 2024-03-01 00:00:00 CET in Unix milliseconds
+	defaultEpoch = 1709247600000
 )
 
 // Option is a function that configures the generator
 type Option func(*Generator)
 
-// TimeFunc is a function that returns the current time in milliseconds
+// TimeFunc is a function that returns the current time in milliseconds, or in the time unit of WithTimeUnit, since the
+// Unix epoch
 type TimeFunc func() uint64
 
 func defaultTimeFunc() uint64 {
 	return uint64(time.Now().UnixMilli())
 }
 
-func defaultSleepFunc() {
-	nano := time.Duration(time.Now().UnixNano())
-	milli := nano.Truncate(time.Millisecond)
-	milli = milli + time.Millisecond
-	delay := milli - nano
-	time.Sleep(delay + 1*time.Nanosecond)
-}
-
 func exactSleepFunc() {
 	currentMilli := time.Now().UnixMilli()
 	for currentMilli == time.Now().UnixMilli() {
@@ -49,18 +73,65 @@ func exactSleepFunc() {
 }
 
 // Generator is a snowflake ID generator
+// All methods are safe for concurrent use, including SetTimeFunc while other goroutines generate IDs
+// The timestamp and sequence are packed in a single word that is updated with a compare and swap, so generating an ID
+// does not take a lock, only WithSequenceStrategy and WithRandomSequenceStart lock to use their state
 type Generator struct {
-	currentID      atomic.Uint64
-	machineID      uint64
-	sequenceMask   uint64
-	machineIDMask  uint64
-	machineIDBits  uint64
-	machineIDShift uint64
-	epoch          int64
-	timeFunc       TimeFunc
-	sleepFunc      func()
-	drift          bool
-	duration       time.Duration
+	currentID       atomic.Uint64
+	machineID       atomic.Uint64
+	sequenceMask    uint64
+	machineIDMask   uint64
+	machineIDShift  uint64
+	layout          Layout
+	shardMask       uint64
+	shardShift      uint64
+	timestampShift  uint64
+	sequenceShift   uint64
+	nonce           uint64
+	nonceShift      uint64
+	version         uint64
+	epoch           int64
+	timeFunc        atomic.Pointer[TimeFunc]
+	sleepFunc       func()
+	drift           bool
+	driftWait       bool
+	strict          bool
+	startupSleep    bool
+	duration        time.Duration
+	tracer          Tracer
+	rateLimiter     *RateLimiter
+	lastCallBlocked atomic.Bool
+	usage           *usageHistogram
+	timing          *generationTiming
+	noRandomID      bool
+	coarse          *coarseClock
+	watchdog        *clockWatchdog
+	history         *history
+	assertMonotonic bool
+	monotonic       bool
+	spinWait        time.Duration
+	signedSafe      bool
+	logical         bool
+	provider        MachineIDProvider
+	recovery        MachineIDProvider
+	collisions      atomic.Uint64
+	floor           atomic.Uint64
+	pastMu          sync.Mutex
+	past            map[uint64]uint64
+	maxFutureOffset time.Duration
+	initialSequence uint64
+	onOverflow      func(timestamp uint64)
+	strategy        SequenceStrategy
+	strategyMu      sync.Mutex
+	quantization    time.Duration
+	step            uint64
+	stateShift      uint64
+	customLayout    bool
+	layoutSequence  uint64
+	tolerance       time.Duration
+	observer        Observer
+	random          *lockedRand
+	unit            time.Duration
 }
 
 // NewGenerator creates a new snowflake ID generator
@@ -69,93 +140,846 @@ type Generator struct {
 // Returns a new snowflake ID generator
 // Returns an error if the machineID is too large for the number of bits
 // Returns an error if the machineIDBits is invalid
+// Returns an error if the shardBits leave no room for the sequence
+// Returns an error if the version does not fit in the version bit
+// Returns an error if the nonce bits leave no room for the sequence or the nonce cannot be generated
+// Returns an error if the initial sequence is too large for the number of sequence bits
+// Returns an error if the time unit is invalid or the current time does not fit in the timestamp bits with it
+// Returns ErrEpochInFuture if the epoch is later than the current time of the time function
 func NewGenerator(machineID uint64, opts ...Option) (*Generator, error) {
+	return NewGeneratorContext(context.Background(), machineID, opts...)
+}
+
+// NewGeneratorContext creates a new snowflake ID generator like NewGenerator, the context is passed to the machine ID
+// provider set with WithMachineIDProvider
+// Returns the error of the context if it is done before the provider returns
+func NewGeneratorContext(ctx context.Context, machineID uint64, opts ...Option) (*Generator, error) {
 	g := &Generator{
-		timeFunc:      defaultTimeFunc,
-		machineIDBits: 10,
-		machineID:     machineID,
-		sleepFunc:     defaultSleepFunc,
-		epoch:         1709247600000,
+		layout: DefaultLayout(),
+		epoch:  defaultEpoch,
+		unit:   time.Millisecond,
+	}
+	g.machineID.Store(machineID)
+	// The time and sleep functions read the unit when they are called, so WithTimeUnit can be set after them
+	g.SetTimeFunc(func() uint64 {
+		if g.unit == time.Millisecond {
+			return defaultTimeFunc()
+		}
+		return uint64(g.ticks(time.Now()))
+	})
+	g.sleepFunc = func() {
+		sleepUntilNext(g.unit)
 	}
 
 	for _, opt := range opts {
 		opt(g)
 	}
 
-	maxMachineID := uint64(1<<g.machineIDBits - 1)
+	if !validTimeUnit(g.unit) {
+		return nil, ErrInvalidTimeUnit
+	}
+	if g.unit != time.Millisecond {
+		g.epoch = g.ticks(time.UnixMilli(g.epoch))
+	}
+
+	if g.provider != nil {
+		id, err := provideMachineID(ctx, g.provider)
+		if err != nil {
+			return nil, err
+		}
+		g.machineID.Store(id)
+	}
+
+	if g.customLayout {
+		if sum := g.layout.TimestampBits + g.layout.MachineIDBits + g.layoutSequence; sum != 63 {
+			return nil, fmt.Errorf("%w: %d timestamp, %d machine ID and %d sequence bits add up to %d instead of 63",
+				ErrInvalidLayout, g.layout.TimestampBits, g.layout.MachineIDBits, g.layoutSequence, sum)
+		}
+	}
+
+	if err := g.layout.validate(); err != nil {
+		return nil, err
+	}
+	if g.customLayout && g.layoutSequence != g.layout.sequenceBits() {
+		return nil, fmt.Errorf("%w: %d sequence bits requested, the shard and nonce bits leave %d", ErrInvalidLayout,
+			g.layoutSequence, g.layout.sequenceBits())
+	}
+
+	if machineID := g.machineID.Load(); machineID > g.layout.machineIDMask() {
+		return nil, machineIDTooLarge(machineID, g.layout.MachineIDBits)
+	}
 
-	if g.machineIDBits < 1 {
-		return nil, ErrMachineBitsTooSmall
+	if g.version > 1 {
+		return nil, ErrVersionTooLarge
 	}
 
-	if g.machineIDBits > 21 {
-		return nil, ErrMachineBitsTooLarge
+	if err := g.validateMonotonic(); err != nil {
+		return nil, err
+	}
+	if g.rateLimiter != nil && g.rateLimiter.err != nil {
+		return nil, g.rateLimiter.err
+	}
+	if g.signedSafe && g.version == 1 {
+		return nil, fmt.Errorf("%w: version 1 sets the most significant bit", ErrIDOutOfRange)
 	}
 
-	if g.machineID > maxMachineID {
-		return nil, ErrMachineIDTooLarge
+	g.machineIDMask = g.layout.machineIDMask()
+	g.machineIDShift = g.layout.machineIDShift()
+	g.shardMask = g.layout.shardMask()
+	g.shardShift = g.layout.shardShift()
+	g.sequenceMask = g.layout.sequenceMask()
+	if g.initialSequence > g.sequenceMask {
+		return nil, ErrSequenceTooLarge
+	}
+	g.step = 1
+	if g.quantization != 0 {
+		if g.quantization < g.unit || g.quantization%g.unit != 0 {
+			return nil, ErrInvalidQuantization
+		}
+		g.step = g.units(g.quantization)
+	}
+	g.timestampShift = g.layout.timestampShift()
+	g.stateShift = g.layout.stateShift()
+	g.sequenceShift = g.layout.sequenceShift()
+	g.nonceShift = g.layout.nonceShift()
+
+	if g.layout.NonceBits > 0 {
+		nonce, err := randomNonce(g.layout.nonceMask())
+		if err != nil {
+			return nil, err
+		}
+		g.nonce = nonce
+	}
+
+	if now := g.now(); !g.logical && int64(now) < g.epoch {
+		return nil, fmt.Errorf("%w: epoch %v is after the current time %v", ErrEpochInFuture, g.Epoch().UTC(),
+			g.timeOf(int64(now)).UTC())
+	}
+	if g.unit != time.Millisecond {
+		if _, err := g.elapsed(g.now()); errors.Is(err, ErrTimestampOverflow) {
+			return nil, fmt.Errorf("%w: with a time unit of %v the timestamp bits lasted until %v", err, g.unit,
+				g.EpochExhaustionTime().UTC())
+		}
 	}
 
-	g.machineIDMask = maxMachineID
-	g.sequenceMask = 1<<(timeShift-g.machineIDBits) - 1
-	g.machineIDShift = timeShift - g.machineIDBits
+	if g.driftWait {
+		time.Sleep(g.duration)
+	}
+	if g.startupSleep {
+		g.sleepFunc()
+	}
 
 	return g, nil
 }
 
+// SetTimeFunc replaces the time function of the generator
+// It is safe to call while other goroutines generate IDs, calls that already read the time keep using the old time
+func (g *Generator) SetTimeFunc(timeFunc TimeFunc) {
+	g.timeFunc.Store(&timeFunc)
+}
+
+// now returns the current time in milliseconds from the time function
+func (g *Generator) now() uint64 {
+	return (*g.timeFunc.Load())()
+}
+
 // NextID generates a new snowflake ID
+// When the timestamp no longer fits in the timestamp bits, see EpochExhaustionTime, ErrTimestampOverflow is returned
+// instead of wrapping the timestamp, also when drift would move past the last millisecond
+// With WithSpinOnExhaustion an exhausted sequence is retried until the clock moves on or the spin time is used up
 func (g *Generator) NextID() (ID, error) {
+	id, err := g.nextID(g.machineID.Load(), 0)
+	if g.spinWait > 0 && errors.Is(err, ErrOutOfSequence) && !g.strict {
+		return g.spinNextID()
+	}
+	return id, err
+}
 
-	now := int64(g.timeFunc()) - g.epoch
+// NextIDWithTime generates a new snowflake ID like NextID, with t as the current time instead of the time function
+// It shares the state with NextID, so a time before the last generated ID is treated like a clock that moved
+// backwards: the ID continues the sequence of the last millisecond, or ErrClockMovedBackwards is returned in strict
+// mode. This makes tests deterministic without replacing the time function of a generator that is also used with it
+// Returns the errors of NextID, and ErrTimeBeforeEpoch if t is before the epoch
+func (g *Generator) NextIDWithTime(t time.Time) (ID, error) {
+	g.lastCallBlocked.Store(false)
+	state, err := g.reserveWith(1, func() uint64 {
+		return uint64(g.ticks(t))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return g.issue(state, g.machineID.Load(), 0), nil
+}
 
-	if now < 0 {
-		return 0, ErrTimeBeforeEpoch
+// NextIDDecoded generates a new snowflake ID like NextID and also returns its components
+// The components are taken from the generated values, which is cheaper than decoding the ID
+func (g *Generator) NextIDDecoded() (ID, DecodedID, error) {
+	g.lastCallBlocked.Store(false)
+	state, err := g.reserve(1)
+	if err != nil {
+		return 0, DecodedID{}, err
+	}
+	machineID := g.machineID.Load()
+	id := g.issue(state, machineID, 0)
+	return id, DecodedID{
+		ID:        uint64(id),
+		Version:   g.version,
+		Timestamp: state >> g.stateShift,
+		MachineID: machineID,
+		Nonce:     g.nonce,
+		Sequence:  state & g.sequenceMask,
+	}, nil
+}
+
+// NextIDAs generates a new snowflake ID with the given machine ID instead of the configured machine ID
+// All machine IDs share the timestamp and sequence of the generator, so IDs are unique regardless of the machine ID
+// Returns an error if the machine ID is too large for the number of bits
+func (g *Generator) NextIDAs(machineID uint64) (ID, error) {
+	if machineID > g.machineIDMask {
+		return 0, machineIDTooLarge(machineID, g.layout.MachineIDBits)
+	}
+	return g.nextID(machineID, 0)
+}
+
+// machineIDTooLarge returns ErrMachineIDTooLarge with the machine ID and the maximum machine ID of the number of bits
+func machineIDTooLarge(machineID uint64, bits uint64) error {
+	return fmt.Errorf("%w: %d does not fit in %d machine ID bits, the maximum is %d", ErrMachineIDTooLarge, machineID,
+		bits, uint64(1)<<bits-1)
+}
+
+// NextIDForShard generates a new snowflake ID for the given shard
+// Returns an error if the shard is too large for the number of shard bits
+func (g *Generator) NextIDForShard(shard uint64) (ID, error) {
+	if shard > g.shardMask {
+		return 0, ErrShardTooLarge
+	}
+	return g.nextID(g.machineID.Load(), shard)
+}
+
+// NextIDPair generates two consecutive snowflake IDs with a single reservation
+// Both IDs share the timestamp and machine ID, and the sequence of b is the sequence of a plus one
+// When only one sequence number is left in the current millisecond it is skipped and the pair is taken from the next
+// millisecond, which requires drift, otherwise ErrOutOfSequence is returned
+func (g *Generator) NextIDPair() (a, b ID, err error) {
+	g.lastCallBlocked.Store(false)
+	state, err := g.reserve(2)
+	if err != nil {
+		return 0, 0, err
+	}
+	machineID := g.machineID.Load()
+	return g.issue(state, machineID, 0), g.issue(state+1, machineID, 0), nil
+}
+
+// NextIDLinked generates a new snowflake ID and returns it with the ID that the generator issued right before it
+// Both are taken from a single atomic reservation, so concurrent calls form one chain: every ID is the prev of
+// exactly one other ID, and no ID can be issued between prev and cur. This includes IDs of other methods, so prev is
+// the last ID of a NextIDPair or ClaimMillisecond call that came before. prev is composed with the current machine
+// ID of the generator, it differs from the issued ID if that was generated with NextIDAs or NextIDForShard
+// For the first ID of the generator prev is zero
+func (g *Generator) NextIDLinked() (prev, cur ID, err error) {
+	g.lastCallBlocked.Store(false)
+	previous, state, err := g.reserveLinked(1, 0, *g.timeFunc.Load())
+	if err != nil {
+		return 0, 0, err
+	}
+	machineID := g.machineID.Load()
+	if previous != 0 {
+		prev = g.compose(previous, machineID, 0)
+	}
+	return prev, g.issue(state, machineID, 0), nil
+}
+
+// NextIDWithPayload generates a new snowflake ID with the given payload in the sequence field
+// The payload replaces the sequence, so IDs with the same payload are only unique because they have a different
+// timestamp. To guarantee this the ID reserves all sequence numbers of its millisecond, no other ID of the generator
+// shares its timestamp. This limits the generator to one payload ID per millisecond: when the current millisecond is
+// already used the ID is taken from the next millisecond, which requires drift, otherwise ErrOutOfSequence is returned
+// DecodeID returns the payload as the sequence
+// Returns ErrSequenceTooLarge if the payload does not fit in the sequence bits
+func (g *Generator) NextIDWithPayload(payload uint64) (ID, error) {
+	if payload > g.sequenceMask {
+		return 0, ErrSequenceTooLarge
+	}
+	g.lastCallBlocked.Store(false)
+	state, err := g.reserve(g.sequenceMask + 1)
+	if err != nil {
+		return 0, err
+	}
+	return g.issue(state|payload, g.machineID.Load(), 0), nil
+}
+
+// ClaimMillisecond generates all IDs that are left in the current millisecond with a single reservation
+// When the current millisecond is partially used only the IDs left in it are returned, which can be a single ID. When
+// it is exhausted, or the clock has moved on, the IDs of a whole millisecond are returned: the next millisecond
+// requires drift, otherwise ErrOutOfSequence is returned. The IDs are in ascending order and owned by the caller
+func (g *Generator) ClaimMillisecond() ([]ID, error) {
+	g.lastCallBlocked.Store(false)
+	state, err := g.reserve(0)
+	if err != nil {
+		return nil, err
+	}
+	machineID := g.machineID.Load()
+	ids := make([]ID, g.sequenceMask-state&g.sequenceMask+1)
+	for i := range ids {
+		ids[i] = g.issue(state+uint64(i), machineID, 0)
+	}
+	return ids, nil
+}
+
+// NextIDs generates n IDs with the same semantics as n calls to NextID, but with one reservation per millisecond
+// instead of one per ID, see NextIDsFunc. The IDs are in ascending order and owned by the caller
+// Returns the error that NextID would return, such as ErrOutOfSequence when the sequence is exhausted without drift,
+// no IDs are returned then
+func (g *Generator) NextIDs(n int) ([]ID, error) {
+	g.lastCallBlocked.Store(false)
+	return g.collectIDs(n, nil)
+}
+
+// BlockingNextIDs generates n IDs like NextIDs, blocking until the next millisecond when the sequence is exhausted
+// like BlockingNextID
+// Returns the error of the context when it is done while blocking, no IDs are returned then
+func (g *Generator) BlockingNextIDs(ctx context.Context, n int) ([]ID, error) {
+	g.lastCallBlocked.Store(false)
+	return g.collectIDs(n, func() error {
+		if ctx == nil {
+			return nil
+		}
+		return ctx.Err()
+	})
+}
+
+// collectIDs generates n IDs into a slice with generateIDs
+func (g *Generator) collectIDs(n int, canceled func() error) ([]ID, error) {
+	if n < 0 {
+		n = 0
+	}
+	ids := make([]ID, 0, n)
+	if _, err := g.generateIDs(n, canceled, func(id ID) error {
+		ids = append(ids, id)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// NextIDsFunc generates n IDs and calls fn with each of them in ascending order, without holding all of them in memory
+// The IDs are reserved per millisecond with a single reservation for all IDs that are left in it, up to the IDs that
+// are still needed, so the cost of the reservation is shared like with ClaimMillisecond. When the sequence is
+// exhausted NextIDsFunc blocks until the next millisecond like BlockingNextID, in strict mode it returns
+// ErrOutOfSequence instead. With a sequence strategy every ID is reserved separately
+// When fn returns an error NextIDsFunc stops and returns the number of IDs fn accepted and the error, the rest of the
+// reservation is not used
+// Returns the number of IDs fn accepted and any error of the reservation
+func (g *Generator) NextIDsFunc(n int, fn func(ID) error) (int, error) {
+	g.lastCallBlocked.Store(false)
+	return g.generateIDs(n, func() error {
+		return nil
+	}, fn)
+}
+
+// generateIDs generates n IDs with one reservation per millisecond and calls fn with each of them
+// When canceled is nil an exhausted sequence is an error, otherwise it blocks until the next millisecond unless in
+// strict mode, checking canceled before every sleep
+func (g *Generator) generateIDs(n int, canceled func() error, fn func(ID) error) (int, error) {
+	produced := 0
+	for produced < n {
+		var state uint64
+		var err error
+		count := uint64(1)
+		if g.strategy != nil {
+			state, err = g.reserve(1)
+		} else {
+			_, state, err = g.reserveLinked(0, uint64(n-produced), *g.timeFunc.Load())
+			count = g.sequenceMask - state&g.sequenceMask + 1
+			if remaining := uint64(n - produced); count > remaining {
+				count = remaining
+			}
+		}
+		if errors.Is(err, ErrOutOfSequence) && canceled != nil && !g.strict {
+			if err := canceled(); err != nil {
+				return produced, err
+			}
+			g.lastCallBlocked.Store(true)
+			g.sleep()
+			continue
+		}
+		if err != nil {
+			return produced, err
+		}
+		machineID := g.machineID.Load()
+		for i := uint64(0); i < count; i++ {
+			if err := fn(g.issue(state+i, machineID, 0)); err != nil {
+				return produced, err
+			}
+			produced++
+		}
+	}
+	return produced, nil
+}
+
+// LocalCounter returns a strictly increasing counter from the timestamp and sequence of the generator
+// The counter is the milliseconds since the epoch shifted left by 22 bits, or by 64 minus the timestamp bits of
+// WithLayout, plus the sequence, without the machine ID
+// and other fields, which is cheaper than composing an ID. It shares the state with NextID, so counters and IDs of
+// the generator never reuse a sequence number
+// Counters are only unique within the generator, they are not globally unique like IDs
+func (g *Generator) LocalCounter() (uint64, error) {
+	g.lastCallBlocked.Store(false)
+	return g.reserve(1)
+}
+
+// nextID generates a new snowflake ID with the given machine ID and shard
+// All machine IDs and shards share the timestamp and sequence, which keeps the IDs unique and ordered
+// It resets LastCallBlocked for the methods that generate an ID with it
+func (g *Generator) nextID(machineID uint64, shard uint64) (ID, error) {
+	g.lastCallBlocked.Store(false)
+	state, err := g.reserve(1)
+	if err != nil {
+		return 0, err
+	}
+	return g.issue(state, machineID, shard), nil
+}
+
+// reserve reserves n consecutive sequence numbers within one millisecond and returns the state of the first one
+// Zero reserves the rest of the millisecond
+func (g *Generator) reserve(n uint64) (uint64, error) {
+	return g.reserveWith(n, *g.timeFunc.Load())
+}
+
+// reserveWith reserves n consecutive sequence numbers like reserve, with the time of the given time function
+func (g *Generator) reserveWith(n uint64, timeFunc TimeFunc) (uint64, error) {
+	_, first, err := g.reserveLinked(n, 0, timeFunc)
+	return first, err
+}
+
+// reserveLinked reserves n consecutive sequence numbers like reserveWith and also returns the state it replaced, which
+// is the state of the last sequence number reserved before, or zero if nothing was reserved yet
+// When n is zero and limit is not zero at most limit sequence numbers of the rest of the millisecond are reserved
+func (g *Generator) reserveLinked(n uint64, limit uint64, timeFunc TimeFunc) (previous uint64, first uint64, err error) {
+	if g.timing != nil {
+		start := time.Now()
+		defer func() {
+			g.timing.record(time.Since(start))
+		}()
+	}
+
+	now, err := g.elapsed(timeFunc())
+	if err != nil {
+		return 0, 0, err
+	}
+	if g.strict && g.tolerance > 0 {
+		if now, err = g.waitForClock(now, timeFunc); err != nil {
+			return 0, 0, err
+		}
+	}
+	if g.coarse != nil {
+		g.coarse.observe(now)
+	}
+	if g.watchdog != nil {
+		if err := g.watchdog.observe(now); err != nil {
+			return 0, 0, err
+		}
+	}
+	if g.strategy != nil {
+		if n != 1 {
+			return 0, 0, ErrSequenceStrategyBatch
+		}
+		return g.reserveFromStrategy(now)
 	}
 
 	for {
 		currentID := g.currentID.Load()
-		newCurrentID := currentID
-		lastTime := currentID >> timeShift
-		sequence := currentID & g.sequenceMask
-		switch {
-		case lastTime < uint64(now):
-			lastTime = uint64(now)
-			newCurrentID = lastTime << timeShift
-		case sequence == g.sequenceMask:
-			if !g.drift {
-				return 0, ErrOutOfSequence
+		count := n
+		if count == 0 {
+			count = g.rest(currentID, now)
+			if limit != 0 && count > limit {
+				count = limit
 			}
-			if lastTime-uint64(now) >= uint64(g.duration.Milliseconds()) {
-				return 0, ErrOutOfSequence
+		}
+		first, newMillisecond, err := g.advance(currentID, now, count)
+		if errors.Is(err, ErrOutOfSequence) {
+			g.sequenceExhausted()
+			if g.onOverflow != nil {
+				g.onOverflow(currentID >> g.stateShift)
 			}
-			newCurrentID = (lastTime + 1) << timeShift
-		default:
-			newCurrentID++
 		}
-		newCurrentID = newCurrentID | (g.machineID << g.machineIDShift)
-		if g.currentID.CompareAndSwap(currentID, newCurrentID) {
-			return ID(newCurrentID), nil
+		if err != nil {
+			return 0, 0, err
+		}
+		if g.assertMonotonic {
+			assertMonotonic(currentID, first)
+		}
+		if currentID == 0 {
+			g.lowerFloor(first)
+		}
+		if g.currentID.CompareAndSwap(currentID, first+count-1) {
+			if g.usage != nil && (newMillisecond || currentID == 0) {
+				g.usage.advance(currentID, first, g.stateShift, g.sequenceMask)
+			}
+			if g.onOverflow != nil && newMillisecond && currentID>>g.stateShift >= now {
+				g.onOverflow(currentID >> g.stateShift)
+			}
+			return currentID, first, nil
 		}
 	}
 }
 
+// elapsed returns the milliseconds, or time units, since the epoch of the given time in Unix milliseconds, or time
+// units, rounded down to the timestamp quantization
+// With a logical clock the time is always the epoch, the timestamp then only advances when the sequence is exhausted
+// Returns an error if the time is before the epoch or does not fit in the timestamp bits
+func (g *Generator) elapsed(unixMilli uint64) (uint64, error) {
+	if g.logical {
+		return 0, nil
+	}
+	now := int64(g.quantize(unixMilli)) - g.epoch
+
+	if now < 0 {
+		return 0, ErrTimeBeforeEpoch
+	}
+
+	if uint64(now) > g.layout.timestampMask() {
+		return 0, ErrTimestampOverflow
+	}
+
+	return uint64(now), nil
+}
+
+// advance returns the state of the first of n sequence numbers after the given state at the given time
+// newMillisecond reports whether the sequence numbers are in a later millisecond than the given state
+func (g *Generator) advance(currentID uint64, now uint64, n uint64) (first uint64, newMillisecond bool, err error) {
+	if currentID == 0 && g.initialSequence > 0 {
+		// Nothing has been generated yet, continue as if the sequence numbers before the initial sequence were used
+		currentID = now<<g.stateShift | g.initialSequence - 1
+	}
+	lastTime := currentID >> g.stateShift
+	sequence := currentID & g.sequenceMask
+	switch {
+	case lastTime < now:
+		return now<<g.stateShift | g.sequenceStart(n), true, nil
+	case g.strict && lastTime > now:
+		return 0, false, fmt.Errorf("%w: %v behind the last generated ID", ErrClockMovedBackwards,
+			time.Duration(lastTime-now)*g.unit)
+	case sequence+n > g.sequenceMask:
+		if g.strict || lastTime-now >= g.driftWindow() {
+			return 0, false, ErrOutOfSequence
+		}
+		if lastTime > g.layout.timestampMask()-g.step {
+			// Drifting past the last millisecond would wrap the timestamp and collide with IDs of the epoch
+			return 0, false, ErrTimestampOverflow
+		}
+		return (lastTime+g.step)<<g.stateShift | g.sequenceStart(n), true, nil
+	default:
+		return currentID + 1, false, nil
+	}
+}
+
+// rest returns the number of sequence numbers that are left in the millisecond advance continues in after the given
+// state, which is a whole millisecond when the clock has moved on or the sequence is exhausted
+func (g *Generator) rest(currentID uint64, now uint64) uint64 {
+	if currentID == 0 && g.initialSequence > 0 {
+		currentID = now<<g.stateShift | g.initialSequence - 1
+	}
+	sequence := currentID & g.sequenceMask
+	if currentID>>g.stateShift < now || sequence == g.sequenceMask {
+		return g.sequenceMask + 1
+	}
+	return g.sequenceMask - sequence
+}
+
+// driftWindow returns how many milliseconds the last generated ID may be ahead of the clock when it moves on to the
+// next millisecond, zero means that an exhausted sequence is an error
+func (g *Generator) driftWindow() uint64 {
+	if g.logical {
+		// The logical clock may move on to any timestamp
+		return g.layout.timestampMask() + 1
+	}
+	var window uint64
+	if g.drift {
+		window = g.units(g.duration)
+	}
+	if g.coarse != nil {
+		if granularity := g.coarse.granularity.Load(); granularity > window+1 {
+			window = granularity - 1
+		}
+	}
+	return window
+}
+
+// PeekNextID returns the ID that the next call to NextID would generate, without generating it
+// This is advisory only: another goroutine or the clock moving on can change the next ID before NextID is called
+// Returns the error that NextID would return
+func (g *Generator) PeekNextID() (ID, error) {
+	now, err := g.elapsed(g.now())
+	if err != nil {
+		return 0, err
+	}
+	first, _, err := g.advance(g.currentID.Load(), now, 1)
+	if err != nil {
+		return 0, err
+	}
+	return g.compose(first, g.machineID.Load(), 0), nil
+}
+
+// issue composes an ID like compose and records it in the history, it is used for the IDs that are handed out
+func (g *Generator) issue(state uint64, machineID uint64, shard uint64) ID {
+	id := g.compose(state, machineID, shard)
+	if g.history != nil {
+		g.history.record(id)
+	}
+	if g.observer != nil {
+		g.observer.IDGenerated()
+	}
+	return id
+}
+
+// compose composes an ID from the timestamp and sequence of the state, the machine ID and the shard
+// The state holds the timestamp in the bits above stateShift and the sequence in the lowest bits, which makes it
+// independent of the field order of the layout
+func (g *Generator) compose(state uint64, machineID uint64, shard uint64) ID {
+	return ID(g.version<<63 |
+		state>>g.stateShift<<g.timestampShift |
+		machineID<<g.machineIDShift |
+		shard<<g.shardShift |
+		g.nonce<<g.nonceShift |
+		state&g.sequenceMask<<g.sequenceShift)
+}
+
 // BlockingNextID generates a new snowflake ID, blocking until the next ID can be generated
+// Returns the error of the context when it is canceled or its deadline passes while blocking, such as
+// context.DeadlineExceeded, which bounds the wait for a stalled clock. The context is checked before every sleep
+// until the next millisecond, context.Background() and a nil context are never canceled
+// In strict mode this does not block and is equivalent to NextID
 func (g *Generator) BlockingNextID(ctx context.Context) (ID, error) {
-	id, err := g.NextID()
+	return g.blockingNextID(canceledBy(ctx), nil, nil)
+}
+
+// canceledBy returns a function that returns the error of the context, a nil context is never canceled
+func canceledBy(ctx context.Context) func() error {
+	return func() error {
+		if ctx == nil {
+			return nil
+		}
+		return ctx.Err()
+	}
+}
+
+// BlockingNextIDDone generates a new snowflake ID, blocking until the next ID can be generated
+// Returns ErrCanceled when the done channel is closed while blocking, a nil channel is never closed
+// The channel is checked before every sleep until the next millisecond, so closing it returns within one sleep
+// In strict mode this does not block and is equivalent to NextID
+func (g *Generator) BlockingNextIDDone(done <-chan struct{}) (ID, error) {
+	return g.blockingNextID(func() error {
+		select {
+		case <-done:
+			return ErrCanceled
+		default:
+			return nil
+		}
+	}, nil, nil)
+}
+
+// blockingNextID generates a new snowflake ID, blocking until the next ID can be generated
+// canceled is checked before every sleep, blocking stops when it returns an error
+// A non-nil tracer records the sequence exhaustion and the duration it blocked with ctx
+func (g *Generator) blockingNextID(canceled func() error, tracer Tracer, ctx context.Context) (ID, error) {
+	if g.strict {
+		id, err := g.NextID()
+		if tracer != nil && errors.Is(err, ErrOutOfSequence) {
+			tracer.SequenceExhausted(ctx)
+		}
+		return id, err
+	}
+	var blocked bool
+	if tracer != nil {
+		start := time.Now()
+		defer func() {
+			if blocked {
+				tracer.Blocked(ctx, time.Since(start))
+			}
+		}()
+	}
+	blocked, err := g.waitForRateToken(canceled)
+	if err != nil {
+		g.lastCallBlocked.Store(blocked)
+		return 0, err
+	}
+	id, err := g.nextID(g.machineID.Load(), 0)
+	if tracer != nil && errors.Is(err, ErrOutOfSequence) {
+		tracer.SequenceExhausted(ctx)
+	}
 	for errors.Is(err, ErrOutOfSequence) {
-		if ctx != nil && ctx.Err() != nil {
-			return 0, ctx.Err()
+		if err := canceled(); err != nil {
+			g.lastCallBlocked.Store(blocked)
+			return 0, err
 		}
-		g.sleepFunc()
-		id, err = g.NextID()
+		blocked = true
+		g.sleep()
+		id, err = g.nextID(g.machineID.Load(), 0)
+	}
+	g.lastCallBlocked.Store(blocked)
+	return id, err
+}
+
+// LastCallBlocked reports whether the most recent NextID or BlockingNextID call had to block
+func (g *Generator) LastCallBlocked() bool {
+	return g.lastCallBlocked.Load()
+}
+
+// MachineID returns the machine ID of the generator, which may have been derived by NewGeneratorAuto or a provider
+func (g *Generator) MachineID() uint64 {
+	return g.machineID.Load()
+}
+
+// Epoch returns the epoch of the generator, which is 2024-03-01 00:00:00 CET unless it is set with WithEpoch
+func (g *Generator) Epoch() time.Time {
+	return g.timeOf(g.epoch)
+}
+
+// LastTimestamp returns the time of the most recently generated ID
+// Returns the zero time if no ID has been generated yet
+func (g *Generator) LastTimestamp() time.Time {
+	currentID := g.currentID.Load()
+	if currentID == 0 {
+		return time.Time{}
+	}
+	return g.timeOf(g.epoch + int64(currentID>>g.stateShift))
+}
+
+// EpochExhaustionTime returns the time at which the timestamp no longer fits in the timestamp bits
+// From this time on NextID returns ErrTimestampOverflow
+func (g *Generator) EpochExhaustionTime() time.Time {
+	return g.timeOf(g.epoch + int64(g.layout.timestampMask()) + 1)
+}
+
+// OverflowTime returns the last time that fits in the timestamp bits, which is the epoch plus 2^timestampBits - 1 time
+// units, one time unit before EpochExhaustionTime
+func (g *Generator) OverflowTime() time.Time {
+	return g.timeOf(g.epoch + int64(g.layout.timestampMask()))
+}
+
+// RemainingLifespan returns the time from the current time of the time function until EpochExhaustionTime
+// Returns zero once the timestamp bits are exhausted, and the largest duration if the lifespan does not fit in one
+func (g *Generator) RemainingLifespan() time.Duration {
+	left := int64(g.layout.timestampMask()) + 1 - (int64(g.now()) - g.epoch)
+	if left <= 0 {
+		return 0
+	}
+	if left > math.MaxInt64/int64(g.unit) {
+		return math.MaxInt64
+	}
+	return time.Duration(left) * g.unit
+}
+
+// Remaining returns the number of IDs that can be generated in the current millisecond without blocking
+// Returns the full capacity if the clock has advanced past the last generated ID, or if the time cannot be used
+// The time is read like NextID reads it, so the timestamp quantization and the logical clock are taken into account
+// Drift is not taken into account
+func (g *Generator) Remaining() uint64 {
+	now, err := g.elapsed(g.now())
+	if err != nil {
+		return g.sequenceMask + 1
+	}
+	currentID := g.currentID.Load()
+	if currentID == 0 || now > currentID>>g.stateShift {
+		return g.sequenceMask + 1
+	}
+	return g.sequenceMask - currentID&g.sequenceMask
+}
+
+// WouldBlock reports whether NextID would find the sequence of the current millisecond exhausted, which makes
+// BlockingNextID wait, and how long until the millisecond after the last generated ID begins
+// The wait is measured with the time function in whole time units, so it is an upper bound of the actual wait. Like
+// PeekNextID it does not generate an ID and another goroutine or the clock moving on can change the answer
+// Returns false and zero when the next ID can be generated without waiting, for example by drifting into the next
+// millisecond, or when NextID would return another error
+func (g *Generator) WouldBlock() (bool, time.Duration) {
+	now, err := g.elapsed(g.now())
+	if err != nil {
+		return false, 0
+	}
+	currentID := g.currentID.Load()
+	if _, _, err = g.advance(currentID, now, 1); !errors.Is(err, ErrOutOfSequence) {
+		return false, 0
 	}
-	return id, nil
+	return true, time.Duration(currentID>>g.stateShift+g.step-now) * g.unit
 }
 
 // WithMachineIDBits sets the number of bits to use for the machine ID
 func WithMachineIDBits(size uint64) Option {
 	return func(generator *Generator) {
-		generator.machineIDBits = size
+		generator.layout.MachineIDBits = size
+	}
+}
+
+// WithLayout sets the number of timestamp, machine ID and sequence bits, which must add up to 63, the most
+// significant bit stays unused
+// More timestamp bits extend the lifetime of the generator beyond the 139 years of the default 42 bits at the cost of
+// sequence bits, for example 45 timestamp bits last 1115 years. WithShardBits and WithInstanceNonceBits take their
+// bits from the sequence, so the sequence bits must account for them. With WithVersionBit the version uses the most
+// significant bit
+// NewGenerator returns ErrInvalidLayout if the bits do not add up to 63, and an error if the machine ID does not fit
+func WithLayout(timestampBits, machineIDBits, sequenceBits uint64) Option {
+	return func(generator *Generator) {
+		generator.layout.TimestampBits = timestampBits
+		generator.layout.MachineIDBits = machineIDBits
+		generator.customLayout = true
+		generator.layoutSequence = sequenceBits
+	}
+}
+
+// WithSingleNodeLayout uses no machine ID bits for a generator that is the only one generating IDs
+// The sequence gets all 22 bits below the timestamp, which allows 4194304 IDs per millisecond, the machine ID must be
+// 0 and is decoded as 0. Shard, nonce and version bits are still taken from the sequence and timestamp as usual
+func WithSingleNodeLayout() Option {
+	return func(generator *Generator) {
+		generator.layout.MachineIDBits = 0
+		generator.layout.SingleNode = true
+	}
+}
+
+// WithHighThroughputLayout uses 2 machine ID bits and 20 sequence bits for bursty workloads in a single datacenter
+// This allows 4 machine IDs, 0 to 3, that each generate up to 1048576 IDs per millisecond
+func WithHighThroughputLayout() Option {
+	return WithMachineIDBits(2)
+}
+
+// WithShardBits sets the number of bits to use for the shard
+// The shard bits are placed between the machine ID and the sequence, and are taken from the sequence
+// Use NextIDForShard to generate an ID for a shard
+func WithShardBits(size uint64) Option {
+	return func(generator *Generator) {
+		generator.layout.ShardBits = size
+	}
+}
+
+// WithFieldOrder sets the order of the fields of the IDs, the default is OrderTimestampMachineSequence
+// Use OrderTimestampSequenceMachine to generate and decode the IDs of systems that place the machine ID in the least
+// significant bits
+// NewGenerator returns ErrInvalidFieldOrder if the order is not one of the FieldOrder constants
+func WithFieldOrder(order FieldOrder) Option {
+	return func(generator *Generator) {
+		generator.layout.Order = order
+	}
+}
+
+// WithSpreadLayout places the sequence in the most significant bits, see OrderSequenceTimestampMachine
+// This spreads consecutive IDs over the key space to avoid write hotspots in databases, at the cost of IDs no longer
+// sorting by time
+func WithSpreadLayout() Option {
+	return func(generator *Generator) {
+		generator.layout.Order = OrderSequenceTimestampMachine
+	}
+}
+
+// WithVersionBit reserves the most significant bit for a version flag and sets it to v on every generated ID
+// This reduces the timestamp to 41 bits, which allows for a maximum of 69 years of IDs since the epoch
+// NewGenerator returns an error if v is not 0 or 1
+func WithVersionBit(v uint8) Option {
+	return func(generator *Generator) {
+		generator.layout.VersionBit = true
+		generator.version = uint64(v)
 	}
 }
 
@@ -166,6 +990,34 @@ func WithEpoch(epoch time.Time) Option {
 	}
 }
 
+// WithInitialSequence sets the sequence of the first ID, for example a counter that is persisted across restarts
+// It only applies to the millisecond of the first ID, later milliseconds start at sequence 0 as usual
+// Fewer IDs are left in the first millisecond, when these are exhausted the generator behaves as if it generated the
+// IDs before the initial sequence itself: NextID returns ErrOutOfSequence, drift moves to the next millisecond and
+// BlockingNextID blocks until the next millisecond
+// NewGenerator returns ErrSequenceTooLarge if the sequence is too large for the number of sequence bits
+func WithInitialSequence(seq uint64) Option {
+	return func(generator *Generator) {
+		generator.initialSequence = seq
+	}
+}
+
+// WithOnSequenceOverflow sets a function that is called when a call finds the sequence of a millisecond exhausted
+// The function receives the exhausted millisecond since the epoch, the configured behaviour still decides the outcome:
+// NextID returns ErrOutOfSequence, drift moves to the next millisecond and BlockingNextID blocks, in which case the
+// function is called again for every retry
+// The function is called on the goroutine that generates the ID, so it should return quickly
+func WithOnSequenceOverflow(fn func(timestamp uint64)) Option {
+	return func(generator *Generator) {
+		generator.onOverflow = fn
+	}
+}
+
+// WithEpochMillis sets the epoch for the generator in Unix milliseconds, it is equivalent to WithEpoch(time.UnixMilli(ms))
+func WithEpochMillis(ms int64) Option {
+	return WithEpoch(time.UnixMilli(ms))
+}
+
 // WithDrift enables drift to continue generating IDs when the sequence overflows
 // This allows the generator to generate IDs for times in the future
 // This increases performance but may generate IDs out of sequence
@@ -173,8 +1025,8 @@ func WithEpoch(epoch time.Time) Option {
 func WithDrift(duration time.Duration) Option {
 	return func(generator *Generator) {
 		generator.drift = true
+		generator.driftWait = true
 		generator.duration = duration
-		time.Sleep(duration)
 	}
 }
 
@@ -190,6 +1042,28 @@ func WithDriftNoWait(duration time.Duration) Option {
 	}
 }
 
+// WithStrict enables strict mode, in which every anomaly is returned as an error
+// The generator never blocks and never generates IDs for a time other than the current time
+// The following conditions return an error in strict mode:
+//   - the clock is before the epoch: ErrTimeBeforeEpoch
+//   - the clock is behind the last generated ID: ErrClockMovedBackwards with the delta, unless it is within the
+//     tolerance of WithClockRollbackTolerance
+//   - the sequence is exhausted, also in BlockingNextID and when drift is enabled: ErrOutOfSequence
+func WithStrict() Option {
+	return func(generator *Generator) {
+		generator.strict = true
+	}
+}
+
+// WithStartupSleep sleeps until the next millisecond in NewGenerator, using the sleep function
+// This prevents reissuing IDs of the millisecond in which a previous instance with the same machine ID stopped, without
+// persisting any state, at the cost of up to a millisecond of startup latency
+func WithStartupSleep() Option {
+	return func(generator *Generator) {
+		generator.startupSleep = true
+	}
+}
+
 // WithExactSleep sets the sleep function to sleep until the next millisecond
 // This implements a busy wait loop to sleep until the next millisecond
 func WithExactSleep() Option {
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 )
@@ -16,9 +19,9 @@ func TestGenerator_NextID(t *testing.T) {
 		t.Errorf("expected no error, got %v", err)
 		return
 	}
-	generator.timeFunc = func() uint64 {
+	generator.SetTimeFunc(func() uint64 {
 		return 367597485448
-	}
+	})
 
 	id, err := generator.NextID()
 	if err != nil {
@@ -40,9 +43,9 @@ func TestGenerator_NextID_WithEpoch(t *testing.T) {
 		return
 	}
 
-	generator.timeFunc = func() uint64 {
+	generator.SetTimeFunc(func() uint64 {
 		return 1656432460105
-	}
+	})
 
 	id, err := generator.NextID()
 	if err != nil {
@@ -55,6 +58,279 @@ func TestGenerator_NextID_WithEpoch(t *testing.T) {
 	}
 }
 
+// TestGenerator_SetTimeFunc_Concurrent tests that the time function can be replaced while IDs are generated
+// Run it with -race to detect data races on the time function
+func TestGenerator_SetTimeFunc_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Hour))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if _, err := generator.NextID(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	for i := uint64(0); i < 1000; i++ {
+		now := 367597485448 + i
+		generator.SetTimeFunc(func() uint64 {
+			return now
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+// TestGenerator_PeekNextID tests that PeekNextID returns the next ID without generating it
+func TestGenerator_PeekNextID(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(21))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	for i := 0; i < 2; i++ {
+		peeked, err := generator.PeekNextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if again, _ := generator.PeekNextID(); again != peeked {
+			t.Errorf("expected %v, got %v", uint64(peeked), uint64(again))
+		}
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if id != peeked {
+			t.Errorf("expected %v, got %v", uint64(peeked), uint64(id))
+		}
+	}
+	if _, err = generator.PeekNextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+}
+
+// TestWithInitialSequence tests that the first millisecond starts at the initial sequence
+func TestWithInitialSequence(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithInitialSequence(4094))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	for _, sequence := range []uint64{4094, 4095} {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, now, sequence)
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+		return
+	}
+	now++
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, now, 0)
+
+	if _, err = NewGenerator(378, WithInitialSequence(4096)); !errors.Is(err, ErrSequenceTooLarge) {
+		t.Errorf("expected ErrSequenceTooLarge, got %v", err)
+	}
+}
+
+// TestWithOnSequenceOverflow tests that the overflow function is called with the exhausted millisecond
+func TestWithOnSequenceOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "without drift", wantErr: ErrOutOfSequence},
+		{name: "with drift", opts: []Option{WithDriftNoWait(time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var overflows []uint64
+			opts := append(tt.opts, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(21),
+				WithOnSequenceOverflow(func(timestamp uint64) {
+					overflows = append(overflows, timestamp)
+				}))
+			generator, err := NewGenerator(378, opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			generator.SetTimeFunc(func() uint64 {
+				return 367597485448
+			})
+			for i := 0; i < 2; i++ {
+				if _, err = generator.NextID(); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+			if len(overflows) != 0 {
+				t.Errorf("expected no overflows, got %v", overflows)
+			}
+			if _, err = generator.NextID(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(overflows) != 1 || overflows[0] != 367597485448 {
+				t.Errorf("expected overflow of 367597485448, got %v", overflows)
+			}
+		})
+	}
+}
+
+// TestWithSingleNodeLayout tests that the single node layout gives the sequence the machine ID bits
+func TestWithSingleNodeLayout(t *testing.T) {
+	generator, err := NewGenerator(0, WithEpoch(time.UnixMilli(0)), WithSingleNodeLayout())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	if generator.sequenceMask != 1<<22-1 {
+		t.Errorf("expected sequence mask %v, got %v", 1<<22-1, generator.sequenceMask)
+	}
+	for sequence := uint64(0); sequence < 5000; sequence++ {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if want := ID(367597485448<<22 | sequence); id != want {
+			t.Errorf("expected %v, got %v", uint64(want), uint64(id))
+			return
+		}
+		verifyRoundTrip(t, generator, id, 367597485448, sequence)
+	}
+
+	if _, err = NewGenerator(1, WithSingleNodeLayout()); !errors.Is(err, ErrMachineIDTooLarge) {
+		t.Errorf("expected ErrMachineIDTooLarge, got %v", err)
+	}
+	if _, err = NewGenerator(0, WithSingleNodeLayout(), WithShardBits(22)); !errors.Is(err, ErrShardBitsTooLarge) {
+		t.Errorf("expected ErrShardBitsTooLarge, got %v", err)
+	}
+}
+
+// TestWithHighThroughputLayout tests the capacity and node count of the high throughput layout
+func TestWithHighThroughputLayout(t *testing.T) {
+	generator, err := NewGenerator(3, WithHighThroughputLayout())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if generator.machineIDMask != 3 {
+		t.Errorf("expected machine ID mask 3, got %v", generator.machineIDMask)
+	}
+	if generator.sequenceMask != 1<<20-1 {
+		t.Errorf("expected sequence mask %v, got %v", 1<<20-1, generator.sequenceMask)
+	}
+	if _, err = NewGenerator(4, WithHighThroughputLayout()); !errors.Is(err, ErrMachineIDTooLarge) {
+		t.Errorf("expected ErrMachineIDTooLarge, got %v", err)
+	}
+}
+
+// TestWithLayout tests that a custom layout places the fields at the configured bits and rejects invalid layouts
+func TestWithLayout(t *testing.T) {
+	generator, err := NewGenerator(5, WithEpoch(time.UnixMilli(0)), WithLayout(45, 10, 8))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	if generator.sequenceMask != 1<<8-1 {
+		t.Errorf("expected sequence mask %v, got %v", 1<<8-1, generator.sequenceMask)
+	}
+	if want := time.UnixMilli(1 << 45); !generator.EpochExhaustionTime().Equal(want) {
+		t.Errorf("expected %v, got %v", want, generator.EpochExhaustionTime())
+	}
+	for sequence := uint64(0); sequence < 256; sequence++ {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if want := ID(367597485448<<18 | 5<<8 | sequence); id != want {
+			t.Errorf("expected %v, got %v", uint64(want), uint64(id))
+			return
+		}
+		verifyRoundTrip(t, generator, id, 367597485448, sequence)
+	}
+	if id, _ := generator.NextID(); id>>63 != 0 {
+		t.Errorf("expected the most significant bit to be unused, got %v", uint64(id))
+	}
+
+	tests := []struct {
+		name      string
+		machineID uint64
+		opts      []Option
+		err       error
+	}{
+		{name: "sum too small", machineID: 1, opts: []Option{WithLayout(41, 10, 11)}, err: ErrInvalidLayout},
+		{name: "sum too large", machineID: 1, opts: []Option{WithLayout(42, 10, 12)}, err: ErrInvalidLayout},
+		{name: "machine ID too large", machineID: 1024, opts: []Option{WithLayout(45, 10, 8)}, err: ErrMachineIDTooLarge},
+		{name: "timestamp bits too large", machineID: 0, opts: []Option{WithLayout(63, 0, 0)}, err: ErrTimestampBitsTooLarge},
+		{
+			name:      "sequence taken by shard",
+			machineID: 1,
+			opts:      []Option{WithLayout(45, 10, 8), WithShardBits(2)},
+			err:       ErrInvalidLayout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGenerator(tt.machineID, tt.opts...); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// TestWithEpochMillis tests that WithEpochMillis sets the same epoch as WithEpoch
+func TestWithEpochMillis(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpochMillis(1288834974657))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if generator.epoch != 1288834974657 {
+		t.Errorf("expected 1288834974657, got %v", generator.epoch)
+	}
+}
+
 // TestGenerator_NextID_GeneratesCorrectAmount tests the NextID method of the Generator to ensure it generates the correct amount of IDs with the default machine ID bit size
 func TestGenerator_NextID_GeneratesCorrectAmount(t *testing.T) {
 	generator, err := NewGenerator(0, WithEpoch(time.UnixMilli(0)))
@@ -63,9 +339,9 @@ func TestGenerator_NextID_GeneratesCorrectAmount(t *testing.T) {
 		return
 	}
 
-	generator.timeFunc = func() uint64 {
+	generator.SetTimeFunc(func() uint64 {
 		return 1
-	}
+	})
 
 	var previousID ID
 	var count uint64
@@ -91,9 +367,9 @@ func TestGenerator_NextID_GeneratesCorrectAmount_WithMachineIdBits(t *testing.T)
 				t.Errorf("expected no error, got %v", err)
 				return
 			}
-			generator.timeFunc = func() uint64 {
+			generator.SetTimeFunc(func() uint64 {
 				return 1
-			}
+			})
 			var previousID ID
 			var count int
 
@@ -122,9 +398,9 @@ func TestGenerator_BlockingNextID_ErrorWhenContextIsCanceledAndBlockingWouldOccu
 		t.Errorf("expected no error, got %v", err)
 		return
 	}
-	generator.timeFunc = func() uint64 {
+	generator.SetTimeFunc(func() uint64 {
 		return uint64(generator.epoch)
-	}
+	})
 	maxCount := 1 << 12
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -137,6 +413,38 @@ func TestGenerator_BlockingNextID_ErrorWhenContextIsCanceledAndBlockingWouldOccu
 	}
 }
 
+// TestGenerator_BlockingNextID_Deadline tests that BlockingNextID stops waiting for a stalled clock at the deadline
+func TestGenerator_BlockingNextID_Deadline(t *testing.T) {
+	generator, err := NewGenerator(378, WithMachineIDBits(21))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// The clock never advances, so the third ID blocks until the deadline
+	generator.SetTimeFunc(func() uint64 {
+		return uint64(generator.epoch) + 1
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if _, err = generator.BlockingNextID(ctx); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	start := time.Now()
+	if _, err = generator.BlockingNextID(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		return
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to stop at the deadline, blocked for %v", elapsed)
+	}
+	if !generator.LastCallBlocked() {
+		t.Errorf("expected the call to block")
+	}
+}
+
 // TestGenerator_BlockingNextID tests the BlockingNextID method of the Generator
 func TestGenerator_BlockingNextID_BlockedUntilNextId(t *testing.T) {
 	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
@@ -144,13 +452,14 @@ func TestGenerator_BlockingNextID_BlockedUntilNextId(t *testing.T) {
 		t.Errorf("expected no error, got %v", err)
 		return
 	}
-	var ts uint64
-	generator.timeFunc = func() uint64 {
-		return ts
-	}
+	generator.SetTimeFunc(func() uint64 {
+		return 0
+	})
 	go func() {
 		time.Sleep(100 * time.Millisecond)
-		ts = 1
+		generator.SetTimeFunc(func() uint64 {
+			return 1
+		})
 	}()
 	maxCount := 1 << 12
 	var id ID
@@ -181,9 +490,9 @@ func TestGenerator_BlockingNextID(t *testing.T) {
 		t.Errorf("expected no error, got %v", err)
 		return
 	}
-	generator.timeFunc = func() uint64 {
+	generator.SetTimeFunc(func() uint64 {
 		return 367597485448
-	}
+	})
 
 	id, err := generator.BlockingNextID(context.TODO())
 	if err != nil {
@@ -206,14 +515,14 @@ func TestGenerator_BlockingNextID_UntilBlock(t *testing.T) {
 		return
 	}
 	var blocked bool
-	generator.timeFunc = func() uint64 {
+	generator.SetTimeFunc(func() uint64 {
 		return 367597485447
-	}
+	})
 	generator.sleepFunc = func() {
 		blocked = true
-		generator.timeFunc = func() uint64 {
+		generator.SetTimeFunc(func() uint64 {
 			return 367597485448
-		}
+		})
 	}
 
 	var previousID ID
@@ -281,6 +590,65 @@ func TestNewGenerator_Errors(t *testing.T) {
 	}
 }
 
+// TestNewGenerator_MachineIDBits tests that the machine ID is validated against the machine ID bits regardless of the
+// order of the options
+func TestNewGenerator_MachineIDBits(t *testing.T) {
+	tests := []struct {
+		name      string
+		bits      uint64
+		machineID uint64
+		want      error
+		message   string
+	}{
+		{name: "1 bit, maximum", bits: 1, machineID: 1},
+		{name: "1 bit, too large", bits: 1, machineID: 2, want: ErrMachineIDTooLarge,
+			message: "machine ID is too large: 2 does not fit in 1 machine ID bits, the maximum is 1"},
+		{name: "5 bits, maximum", bits: 5, machineID: 31},
+		{name: "5 bits, too large", bits: 5, machineID: 378, want: ErrMachineIDTooLarge,
+			message: "machine ID is too large: 378 does not fit in 5 machine ID bits, the maximum is 31"},
+		{name: "10 bits, maximum", bits: 10, machineID: 1023},
+		{name: "10 bits, too large", bits: 10, machineID: 5000, want: ErrMachineIDTooLarge,
+			message: "machine ID is too large: 5000 does not fit in 10 machine ID bits, the maximum is 1023"},
+		{name: "16 bits, maximum", bits: 16, machineID: 65535},
+		{name: "16 bits, too large", bits: 16, machineID: 65536, want: ErrMachineIDTooLarge,
+			message: "machine ID is too large: 65536 does not fit in 16 machine ID bits, the maximum is 65535"},
+		{name: "21 bits, maximum", bits: 21, machineID: 1<<21 - 1},
+		{name: "21 bits, too large", bits: 21, machineID: 1 << 21, want: ErrMachineIDTooLarge,
+			message: "machine ID is too large: 2097152 does not fit in 21 machine ID bits, the maximum is 2097151"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := [][]Option{
+				{WithMachineIDBits(tt.bits), WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second)},
+				{WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second), WithMachineIDBits(tt.bits)},
+			}
+			for _, opts := range orders {
+				generator, err := NewGenerator(tt.machineID, opts...)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("expected %v, got %v", tt.want, err)
+					return
+				}
+				if err != nil {
+					if err.Error() != tt.message {
+						t.Errorf("expected %v, got %v", tt.message, err)
+						return
+					}
+					continue
+				}
+				id, err := generator.NextID()
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				if got := generator.DecodeID(id).MachineID; got != tt.machineID {
+					t.Errorf("expected %v, got %v", tt.machineID, got)
+					return
+				}
+			}
+		})
+	}
+}
+
 // TestDefaultTimeFunc tests the defaultTimeFunc function
 func TestDefaultTimeFunc(t *testing.T) {
 	now := defaultTimeFunc()
@@ -360,12 +728,26 @@ func TestWithDriftNoWait(t *testing.T) {
 	}
 }
 
+// TestGenerator_NextID_InvalidEpoch tests that an epoch in the future fails NewGenerator and a clock that moves back
+// before the epoch fails NextID
 func TestGenerator_NextID_InvalidEpoch(t *testing.T) {
-	generator, err := NewGenerator(378, WithEpoch(time.Now().Add(time.Hour)))
+	if _, err := NewGenerator(378, WithEpoch(time.Now().Add(time.Hour))); !errors.Is(err, ErrEpochInFuture) {
+		t.Errorf("expected ErrEpochInFuture, got %v", err)
+	}
+	if _, err := NewGenerator(378, WithEpoch(time.Now().Add(time.Hour)), WithLogicalClock()); err != nil {
+		t.Errorf("expected no error with a logical clock, got %v", err)
+	}
+
+	epoch := time.Now().Add(-time.Hour)
+	generator, err := NewGenerator(378, WithEpoch(epoch))
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 		return
 	}
+	// The clock moves back before the epoch after the generator is created
+	generator.SetTimeFunc(func() uint64 {
+		return uint64(epoch.UnixMilli() - 1)
+	})
 	id, err := generator.NextID()
 	if !errors.Is(err, ErrTimeBeforeEpoch) {
 		t.Errorf("expected ErrTimeBeforeEpoch, got %v", err)
@@ -382,9 +764,9 @@ func TestNewGenerator_NextId_WithDrif2ms_Returns3x(t *testing.T) {
 		return
 	}
 
-	generator.timeFunc = func() uint64 {
+	generator.SetTimeFunc(func() uint64 {
 		return 1
-	}
+	})
 
 	var previousID ID
 	var count uint64
@@ -399,3 +781,1396 @@ func TestNewGenerator_NextId_WithDrif2ms_Returns3x(t *testing.T) {
 		t.Errorf("expected %v ids, got %v", maxCount, count)
 	}
 }
+
+// TestGenerator_MachineID tests the MachineID method of the Generator
+func TestGenerator_MachineID(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.MachineID(); got != 378 {
+		t.Errorf("expected 378, got %v", got)
+	}
+}
+
+// TestGenerator_Epoch tests the Epoch method of the Generator
+func TestGenerator_Epoch(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want time.Time
+	}{
+		{name: "default epoch", want: time.UnixMilli(1709247600000)},
+		{name: "Twitter epoch", opts: []Option{WithEpoch(time.UnixMilli(1288834974657))}, want: time.UnixMilli(1288834974657)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(378, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.Epoch(); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGenerator_LastTimestamp tests the LastTimestamp method of the Generator
+func TestGenerator_LastTimestamp(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 1656432460105
+	})
+
+	if last := generator.LastTimestamp(); !last.IsZero() {
+		t.Errorf("expected zero time, got %v", last)
+	}
+
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	if last := generator.LastTimestamp(); !last.Equal(time.UnixMilli(1656432460105)) {
+		t.Errorf("expected %v, got %v", time.UnixMilli(1656432460105), last)
+	}
+}
+
+// TestGenerator_NextIDForShard tests the NextIDForShard method of the Generator
+func TestGenerator_NextIDForShard(t *testing.T) {
+	generator, err := NewGenerator(5, WithMachineIDBits(8), WithShardBits(6), WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	for i, shard := range []uint64{0, 1, 63, 1} {
+		id, err := generator.NextIDForShard(shard)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		want := DecodedID{ID: uint64(id), Timestamp: 367597485448, MachineID: 5, Shard: shard, Sequence: uint64(i)}
+		if got := generator.DecodeID(id); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if generator.sequenceMask != 1<<8-1 {
+		t.Errorf("expected sequence mask %v, got %v", 1<<8-1, generator.sequenceMask)
+	}
+}
+
+// TestGenerator_NextIDPair tests that NextIDPair returns consecutive IDs within the same millisecond
+func TestGenerator_NextIDPair(t *testing.T) {
+	// With 21 machine ID bits there are two sequence numbers per millisecond
+	generator, err := NewGenerator(5, WithMachineIDBits(21), WithEpoch(time.UnixMilli(0)), WithDrift(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	a, b, err := generator.NextIDPair()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, a, 367597485448, 0)
+	verifyRoundTrip(t, generator, b, 367597485448, 1)
+
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// Only one sequence number is left, so the pair is taken from the next millisecond
+	a, b, err = generator.NextIDPair()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, a, 367597485450, 0)
+	verifyRoundTrip(t, generator, b, 367597485450, 1)
+}
+
+// TestGenerator_NextIDPair_OutOfSequence tests that NextIDPair does not split a pair without drift
+func TestGenerator_NextIDPair_OutOfSequence(t *testing.T) {
+	generator, err := NewGenerator(5, WithMachineIDBits(21), WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, _, err = generator.NextIDPair(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+		return
+	}
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected the remaining sequence number to be available, got %v", err)
+	}
+}
+
+// TestGenerator_NextIDLinked tests that NextIDLinked returns the previously issued ID with the new one
+func TestGenerator_NextIDLinked(t *testing.T) {
+	generator, err := NewGenerator(5, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	prev, cur, err := generator.NextIDLinked()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if prev != 0 {
+		t.Errorf("expected zero for the first ID, got %v", prev)
+		return
+	}
+	verifyRoundTrip(t, generator, cur, 367597485448, 0)
+
+	_, b, err := generator.NextIDPair()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	prev, cur, err = generator.NextIDLinked()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if prev != b {
+		t.Errorf("expected %v, got %v", b, prev)
+		return
+	}
+	verifyRoundTrip(t, generator, cur, 367597485448, 3)
+}
+
+// TestGenerator_NextIDLinked_Concurrent tests that concurrent calls form a single chain
+func TestGenerator_NextIDLinked_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(5, WithDriftNoWait(time.Hour))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	const goroutines, perGoroutine = 4, 1000
+	links := make(chan [2]ID, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				prev, cur, err := generator.NextIDLinked()
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				links <- [2]ID{prev, cur}
+			}
+		}()
+	}
+	wg.Wait()
+	close(links)
+
+	next := make(map[ID]ID)
+	for link := range links {
+		if _, ok := next[link[0]]; ok {
+			t.Errorf("expected %v to be the prev of one ID", link[0])
+			return
+		}
+		next[link[0]] = link[1]
+	}
+	count := 0
+	for id, ok := next[0]; ok; id, ok = next[id] {
+		count++
+	}
+	if count != goroutines*perGoroutine {
+		t.Errorf("expected a chain of %d IDs, got %d", goroutines*perGoroutine, count)
+	}
+}
+
+// TestGenerator_NextIDWithPayload tests that payload IDs take a millisecond of their own
+func TestGenerator_NextIDWithPayload(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	// The second ID has the same payload, so it is taken from the next millisecond
+	for _, timestamp := range []uint64{367597485448, 367597485449} {
+		id, err := generator.NextIDWithPayload(42)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, timestamp, 42)
+	}
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, 367597485450, 0)
+
+	if _, err = generator.NextIDWithPayload(1 << 12); !errors.Is(err, ErrSequenceTooLarge) {
+		t.Errorf("expected ErrSequenceTooLarge, got %v", err)
+	}
+}
+
+// TestGenerator_NextIDWithPayload_OutOfSequence tests that a second payload ID in a millisecond fails without drift
+func TestGenerator_NextIDWithPayload_OutOfSequence(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	if _, err = generator.NextIDWithPayload(1); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextIDWithPayload(2); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+}
+
+// TestGenerator_ClaimMillisecond tests that ClaimMillisecond claims the IDs that are left in the millisecond
+func TestGenerator_ClaimMillisecond(t *testing.T) {
+	// With 20 machine ID bits there are four sequence numbers per millisecond
+	generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	tests := []struct {
+		name      string
+		advance   uint64
+		timestamp uint64
+		sequence  uint64
+	}{
+		{name: "partially used millisecond", timestamp: 367597485448, sequence: 1},
+		{name: "exhausted millisecond", timestamp: 367597485449, sequence: 0},
+		{name: "clock moved on", advance: 5, timestamp: 367597485453, sequence: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now += tt.advance
+			ids, err := generator.ClaimMillisecond()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if len(ids) != int(4-tt.sequence) {
+				t.Errorf("expected %v IDs, got %v", 4-tt.sequence, len(ids))
+				return
+			}
+			for i, id := range ids {
+				verifyRoundTrip(t, generator, id, tt.timestamp, tt.sequence+uint64(i))
+			}
+		})
+	}
+}
+
+// TestGenerator_ClaimMillisecond_OutOfSequence tests that ClaimMillisecond fails on an exhausted millisecond without
+// drift
+func TestGenerator_ClaimMillisecond_OutOfSequence(t *testing.T) {
+	generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	if _, err = generator.ClaimMillisecond(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.ClaimMillisecond(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+}
+
+// TestGenerator_NextIDs tests that NextIDs generates the same IDs as repeated calls to NextID
+func TestGenerator_NextIDs(t *testing.T) {
+	newGenerator := func() *Generator {
+		// With 20 machine ID bits there are four sequence numbers per millisecond
+		generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)),
+			WithDriftNoWait(time.Second))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		generator.SetTimeFunc(func() uint64 {
+			return 367597485448
+		})
+		return generator
+	}
+	batch, loop := newGenerator(), newGenerator()
+	for _, n := range []int{0, 1, 6, 9} {
+		ids, err := batch.NextIDs(n)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if len(ids) != n {
+			t.Errorf("expected %v IDs, got %v", n, len(ids))
+			return
+		}
+		for _, id := range ids {
+			want, err := loop.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if id != want {
+				t.Errorf("expected %v, got %v", want, id)
+				return
+			}
+		}
+	}
+}
+
+// TestGenerator_NextIDs_OutOfSequence tests that NextIDs fails like NextID when the sequence is exhausted
+func TestGenerator_NextIDs_OutOfSequence(t *testing.T) {
+	generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	ids, err := generator.NextIDs(5)
+	if !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+	if ids != nil {
+		t.Errorf("expected no IDs, got %v", ids)
+	}
+}
+
+// TestGenerator_BlockingNextIDs tests that BlockingNextIDs blocks on an exhausted sequence and stops when canceled
+func TestGenerator_BlockingNextIDs(t *testing.T) {
+	clock := NewManualClock(time.UnixMilli(367597485448))
+	generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)), WithClockInterface(clock))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ids, err := generator.BlockingNextIDs(context.Background(), 6)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	for i, id := range ids {
+		verifyRoundTrip(t, generator, id, 367597485448+uint64(i/4), uint64(i%4))
+	}
+	if !generator.LastCallBlocked() {
+		t.Errorf("expected the call to block")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := generator.BlockingNextIDs(ctx, 6); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+// BenchmarkGenerator_BlockingNextIDs benchmarks generating 1000 IDs with a single BlockingNextIDs call
+func BenchmarkGenerator_BlockingNextIDs(b *testing.B) {
+	generator, _ := NewGenerator(378)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = generator.BlockingNextIDs(context.Background(), 1000)
+	}
+}
+
+// BenchmarkGenerator_BlockingNextIDLoop benchmarks generating 1000 IDs with BlockingNextID in a loop
+func BenchmarkGenerator_BlockingNextIDLoop(b *testing.B) {
+	generator, _ := NewGenerator(378)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ids := make([]ID, 1000)
+		for j := range ids {
+			ids[j], _ = generator.BlockingNextID(context.Background())
+		}
+	}
+}
+
+// TestGenerator_NextIDsFunc tests that NextIDsFunc reserves per millisecond and blocks on an exhausted sequence
+func TestGenerator_NextIDsFunc(t *testing.T) {
+	// With 20 machine ID bits there are four sequence numbers per millisecond
+	clock := NewManualClock(time.UnixMilli(367597485448))
+	generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)), WithClockInterface(clock))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	reads := 0
+	timeFunc := *generator.timeFunc.Load()
+	generator.SetTimeFunc(func() uint64 {
+		reads++
+		return timeFunc()
+	})
+
+	var ids []ID
+	produced, err := generator.NextIDsFunc(9, func(id ID) error {
+		ids = append(ids, id)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if produced != 9 || len(ids) != 9 {
+		t.Errorf("expected 9 IDs, got %v and %v", produced, len(ids))
+		return
+	}
+	for i, id := range ids {
+		// The first millisecond has three IDs left, the IDs after it fill whole milliseconds
+		timestamp, sequence := uint64(367597485448), uint64(i+1)
+		if i >= 3 {
+			timestamp, sequence = 367597485449+uint64(i-3)/4, uint64(i-3)%4
+		}
+		verifyRoundTrip(t, generator, id, timestamp, sequence)
+	}
+	// One reservation per millisecond and one failed reservation before every sleep
+	if reads != 5 {
+		t.Errorf("expected 5 reservations, got %v", reads)
+	}
+	if !generator.LastCallBlocked() {
+		t.Errorf("expected the call to block")
+	}
+}
+
+// TestGenerator_NextIDsFunc_Error tests that NextIDsFunc stops at the first error of fn or of the reservation
+func TestGenerator_NextIDsFunc_Error(t *testing.T) {
+	generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)), WithStrict())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	expected := errors.New("stop")
+	produced, err := generator.NextIDsFunc(3, func(id ID) error {
+		if DecodeID(id, generator.Layout()).Sequence == 1 {
+			return expected
+		}
+		return nil
+	})
+	if !errors.Is(err, expected) || produced != 1 {
+		t.Errorf("expected 1 ID and %v, got %v and %v", expected, produced, err)
+		return
+	}
+
+	// The third ID of the first call was reserved but not used, one ID is left and strict mode does not block
+	produced, err = generator.NextIDsFunc(3, func(ID) error {
+		return nil
+	})
+	if !errors.Is(err, ErrOutOfSequence) || produced != 1 {
+		t.Errorf("expected 1 ID and %v, got %v and %v", ErrOutOfSequence, produced, err)
+	}
+}
+
+// TestGenerator_LocalCounter tests that LocalCounter is strictly increasing and shares the state with NextID
+func TestGenerator_LocalCounter(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	want := []uint64{367597485448 << 22, 367597485448<<22 | 2, 367597485449 << 22}
+	var got []uint64
+	for i, advance := range []uint64{0, 0, 1} {
+		now += advance
+		if i == 1 {
+			if _, err := generator.NextID(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+		}
+		counter, err := generator.LocalCounter()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		got = append(got, counter)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestGenerator_NextIDWithTime tests that NextIDWithTime uses the given time and shares the state with NextID
+func TestGenerator_NextIDWithTime(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	tests := []struct {
+		name      string
+		next      func() (ID, error)
+		timestamp uint64
+		sequence  uint64
+	}{
+		{
+			name: "given time",
+			next: func() (ID, error) {
+				return generator.NextIDWithTime(time.UnixMilli(367597485450))
+			},
+			timestamp: 367597485450,
+			sequence:  0,
+		},
+		{
+			name: "same time",
+			next: func() (ID, error) {
+				return generator.NextIDWithTime(time.UnixMilli(367597485450))
+			},
+			timestamp: 367597485450,
+			sequence:  1,
+		},
+		{
+			name:      "time function before the last ID",
+			next:      generator.NextID,
+			timestamp: 367597485450,
+			sequence:  2,
+		},
+		{
+			name: "later time",
+			next: func() (ID, error) {
+				return generator.NextIDWithTime(time.UnixMilli(367597485451))
+			},
+			timestamp: 367597485451,
+			sequence:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := tt.next()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			verifyRoundTrip(t, generator, id, tt.timestamp, tt.sequence)
+		})
+	}
+}
+
+// TestGenerator_NextIDWithTime_Errors tests the errors of NextIDWithTime
+func TestGenerator_NextIDWithTime_Errors(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(1288834974657)), WithStrict())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextIDWithTime(time.UnixMilli(1288834974656)); !errors.Is(err, ErrTimeBeforeEpoch) {
+		t.Errorf("expected ErrTimeBeforeEpoch, got %v", err)
+	}
+	if _, err = generator.NextIDWithTime(time.UnixMilli(1656432460105)); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextIDWithTime(time.UnixMilli(1656432460104)); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("expected ErrClockMovedBackwards, got %v", err)
+	}
+}
+
+// TestGenerator_NextIDForShard_Errors tests the shard validation of the Generator
+func TestGenerator_NextIDForShard_Errors(t *testing.T) {
+	if _, err := NewGenerator(5, WithMachineIDBits(10), WithShardBits(12)); !errors.Is(err, ErrShardBitsTooLarge) {
+		t.Errorf("expected ErrShardBitsTooLarge, got %v", err)
+	}
+
+	generator, err := NewGenerator(5, WithShardBits(4))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextIDForShard(16); !errors.Is(err, ErrShardTooLarge) {
+		t.Errorf("expected ErrShardTooLarge, got %v", err)
+	}
+}
+
+// TestGenerator_NextIDAs tests the NextIDAs method of the Generator
+func TestGenerator_NextIDAs(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	id, err := generator.NextIDAs(5)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.DecodeID(id); got.MachineID != 5 || got.Sequence != 0 {
+		t.Errorf("expected machine ID 5 and sequence 0, got %v", got)
+	}
+
+	id, err = generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.DecodeID(id); got.MachineID != 378 || got.Sequence != 1 {
+		t.Errorf("expected machine ID 378 and sequence 1, got %v", got)
+	}
+
+	if _, err = generator.NextIDAs(1 << 10); !errors.Is(err, ErrMachineIDTooLarge) {
+		t.Errorf("expected ErrMachineIDTooLarge, got %v", err)
+	}
+}
+
+// TestGenerator_NextIDAs_Concurrent tests that NextIDAs generates unique IDs when called concurrently with different
+// machine IDs
+func TestGenerator_NextIDAs_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(0)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	const goroutines = 8
+	const count = 10000
+	results := make(chan []ID, goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func(machineID uint64) {
+			ids := make([]ID, 0, count)
+			for len(ids) < count {
+				id, err := generator.NextIDAs(machineID)
+				if err == nil {
+					ids = append(ids, id)
+				}
+			}
+			results <- ids
+		}(uint64(i % 2))
+	}
+
+	seen := make(map[uint64]struct{}, goroutines*count)
+	for i := 0; i < goroutines; i++ {
+		for _, id := range <-results {
+			decoded := generator.DecodeID(id)
+			key := decoded.Timestamp<<32 | decoded.Sequence
+			if _, ok := seen[key]; ok {
+				t.Errorf("duplicate timestamp and sequence in %v", decoded)
+				return
+			}
+			seen[key] = struct{}{}
+		}
+	}
+}
+
+// TestGenerator_BlockingNextID_Concurrent tests that BlockingNextID generates unique and, per goroutine, strictly
+// increasing IDs when called concurrently
+func TestGenerator_BlockingNextID_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	const goroutines = 8
+	const count = 10000
+	results := make(chan []ID, goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			ids := make([]ID, 0, count)
+			for len(ids) < count {
+				id, err := generator.BlockingNextID(context.Background())
+				if err == nil {
+					ids = append(ids, id)
+				}
+			}
+			results <- ids
+		}()
+	}
+
+	seen := make(map[ID]struct{}, goroutines*count)
+	for i := 0; i < goroutines; i++ {
+		ids := <-results
+		if err := CheckMonotonicUnique(ids); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				t.Errorf("duplicate ID %v", Debug(generator, id))
+				return
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+// BenchmarkGenerator_NextID_Parallel benchmarks NextID from concurrent goroutines, which only compare and swap the state
+func BenchmarkGenerator_NextID_Parallel(b *testing.B) {
+	generator, _ := NewGenerator(378, WithDriftNoWait(time.Hour))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = generator.NextID()
+		}
+	})
+}
+
+// BenchmarkGenerator_NextID_ParallelLocked benchmarks NextID from concurrent goroutines with a sequence strategy, which
+// locks for every ID, for comparison with BenchmarkGenerator_NextID_Parallel
+func BenchmarkGenerator_NextID_ParallelLocked(b *testing.B) {
+	generator, _ := NewGenerator(378, WithDriftNoWait(time.Hour), WithSequenceStrategy(NewIncrementSequenceStrategy(12)))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = generator.NextID()
+		}
+	})
+}
+
+// TestGenerator_Remaining tests the Remaining method of the Generator
+func TestGenerator_Remaining(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	if got := generator.Remaining(); got != 4096 {
+		t.Errorf("expected 4096, got %v", got)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if got := generator.Remaining(); got != 4086 {
+		t.Errorf("expected 4086, got %v", got)
+	}
+	for err == nil {
+		_, err = generator.NextID()
+	}
+	if got := generator.Remaining(); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+	now++
+	if got := generator.Remaining(); got != 4096 {
+		t.Errorf("expected 4096, got %v", got)
+	}
+}
+
+// TestGenerator_Remaining_Quantized tests that Remaining reads the time quantized like NextID
+func TestGenerator_Remaining_Quantized(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)),
+		WithTimestampQuantization(10*time.Millisecond))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485440)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	for i := 0; i < 10; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	// The clock moved on, but the timestamp is still the one of the last generated ID
+	now += 5
+	if got := generator.Remaining(); got != 4086 {
+		t.Errorf("expected 4086, got %v", got)
+	}
+	now += 5
+	if got := generator.Remaining(); got != 4096 {
+		t.Errorf("expected 4096, got %v", got)
+	}
+}
+
+// TestGenerator_WouldBlock tests that WouldBlock reports an exhausted sequence and the wait until the next millisecond
+func TestGenerator_WouldBlock(t *testing.T) {
+	// With 20 machine ID bits there are four sequence numbers per millisecond
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(20))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	if blocked, wait := generator.WouldBlock(); blocked || wait != 0 {
+		t.Errorf("expected false and 0, got %v and %v", blocked, wait)
+		return
+	}
+	for i := 0; i < 4; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if blocked, _ := generator.WouldBlock(); blocked != (i == 3) {
+			t.Errorf("expected %v after %v IDs, got %v", i == 3, i+1, blocked)
+			return
+		}
+	}
+	if blocked, wait := generator.WouldBlock(); !blocked || wait != time.Millisecond {
+		t.Errorf("expected true and 1ms, got %v and %v", blocked, wait)
+		return
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+		return
+	}
+	now -= 2
+	if blocked, wait := generator.WouldBlock(); !blocked || wait != 3*time.Millisecond {
+		t.Errorf("expected true and 3ms, got %v and %v", blocked, wait)
+		return
+	}
+	now += 3
+	if blocked, wait := generator.WouldBlock(); blocked || wait != 0 {
+		t.Errorf("expected false and 0, got %v and %v", blocked, wait)
+	}
+}
+
+// TestGenerator_WouldBlock_Drift tests that WouldBlock reports false when the generator drifts into the next
+// millisecond
+func TestGenerator_WouldBlock_Drift(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(20),
+		WithDriftNoWait(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	for i := 0; i < 4; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if blocked, wait := generator.WouldBlock(); blocked || wait != 0 {
+		t.Errorf("expected false and 0, got %v and %v", blocked, wait)
+	}
+}
+
+// TestWithStrict tests that strict mode returns errors instead of blocking or drifting
+func TestWithStrict(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second), WithStrict())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	generator.sleepFunc = func() {
+		t.Errorf("expected no sleep in strict mode")
+	}
+
+	for i := uint64(0); i <= generator.sequenceMask; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+	if _, err = generator.BlockingNextID(context.TODO()); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+
+	now--
+	if _, err = generator.NextID(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("expected ErrClockMovedBackwards, got %v", err)
+	}
+}
+
+// TestWithVersionBit tests that WithVersionBit sets the version bit on every generated ID
+func TestWithVersionBit(t *testing.T) {
+	for _, version := range []uint8{0, 1} {
+		t.Run(fmt.Sprintf("TestWithVersionBit=%v", version), func(t *testing.T) {
+			generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithVersionBit(version))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			generator.SetTimeFunc(func() uint64 {
+				return 367597485448
+			})
+			id, err := generator.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if want := ID(uint64(version)<<63 | 1541815603606036480); id != want {
+				t.Errorf("expected %v, got %v", uint64(want), uint64(id))
+			}
+			want := DecodedID{ID: uint64(id), Version: uint64(version), Timestamp: 367597485448, MachineID: 378}
+			if got := generator.DecodeID(id); got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestWithSpreadLayout tests that WithSpreadLayout places the sequence in the most significant bits
+func TestWithSpreadLayout(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithSpreadLayout())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	for sequence := uint64(0); sequence < 3; sequence++ {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if want := ID(sequence<<52 | 367597485448<<10 | 378); id != want {
+			t.Errorf("expected %v, got %v", uint64(want), uint64(id))
+		}
+		verifyRoundTrip(t, generator, id, 367597485448, sequence)
+	}
+}
+
+// TestWithVersionBit_Errors tests the version validation and the reduced timestamp bits of WithVersionBit
+func TestWithVersionBit_Errors(t *testing.T) {
+	if _, err := NewGenerator(378, WithVersionBit(2)); !errors.Is(err, ErrVersionTooLarge) {
+		t.Errorf("expected ErrVersionTooLarge, got %v", err)
+	}
+
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithVersionBit(1))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 1 << 41
+	})
+	if _, err = generator.NextID(); !errors.Is(err, ErrTimestampOverflow) {
+		t.Errorf("expected ErrTimestampOverflow, got %v", err)
+	}
+}
+
+// TestWithStartupSleep tests that WithStartupSleep sleeps once in NewGenerator using the sleep function
+func TestWithStartupSleep(t *testing.T) {
+	var sleeps int
+	withCountingSleep := func(generator *Generator) {
+		generator.sleepFunc = func() {
+			sleeps++
+		}
+	}
+
+	if _, err := NewGenerator(378, withCountingSleep); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if sleeps != 0 {
+		t.Errorf("expected no sleep without WithStartupSleep, got %v", sleeps)
+	}
+
+	if _, err := NewGenerator(378, withCountingSleep, WithStartupSleep()); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if sleeps != 1 {
+		t.Errorf("expected 1 sleep, got %v", sleeps)
+	}
+}
+
+// TestGenerator_LastCallBlocked tests that LastCallBlocked reflects whether the most recent call blocked
+func TestGenerator_LastCallBlocked(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	generator.sleepFunc = func() {
+		now++
+	}
+
+	if generator.LastCallBlocked() {
+		t.Errorf("expected LastCallBlocked to be false before any call")
+	}
+	for i := uint64(0); i <= generator.sequenceMask; i++ {
+		if _, err = generator.BlockingNextID(context.TODO()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if generator.LastCallBlocked() {
+			t.Errorf("expected LastCallBlocked to be false while the sequence is not exhausted")
+			return
+		}
+	}
+	if _, err = generator.BlockingNextID(context.TODO()); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if !generator.LastCallBlocked() {
+		t.Errorf("expected LastCallBlocked to be true after the sequence was exhausted")
+	}
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if generator.LastCallBlocked() {
+		t.Errorf("expected LastCallBlocked to be false after NextID")
+	}
+}
+
+// TestGenerator_LastCallBlocked_Reset tests that every method that generates an ID without blocking resets
+// LastCallBlocked
+func TestGenerator_LastCallBlocked_Reset(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	tests := []struct {
+		name string
+		next func() (ID, error)
+	}{
+		{name: "NextID", next: generator.NextID},
+		{name: "NextIDAs", next: func() (ID, error) { return generator.NextIDAs(1) }},
+		{name: "NextIDForShard", next: func() (ID, error) { return generator.NextIDForShard(0) }},
+		{name: "NextIDWithPayload", next: func() (ID, error) { return generator.NextIDWithPayload(7) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now++
+			generator.lastCallBlocked.Store(true)
+			if _, err := tt.next(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if generator.LastCallBlocked() {
+				t.Errorf("expected LastCallBlocked to be false after %v", tt.name)
+			}
+		})
+	}
+}
+
+// TestGenerator_EpochExhaustionTime tests the EpochExhaustionTime method of the Generator
+func TestGenerator_EpochExhaustionTime(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want time.Time
+	}{
+		{
+			name: "Test EpochExhaustionTime with the default 42 bit timestamp and epoch",
+			want: time.Date(2163, 7, 14, 6, 35, 11, 104e6, time.UTC),
+		},
+		{
+			name: "Test EpochExhaustionTime with a 41 bit timestamp and the Twitter epoch",
+			opts: []Option{WithEpoch(time.UnixMilli(1288834974657)), WithVersionBit(0)},
+			want: time.Date(2080, 7, 10, 17, 30, 30, 209e6, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(378, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.EpochExhaustionTime(); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got.UTC())
+			}
+		})
+	}
+}
+
+// TestGenerator_OverflowTime tests that OverflowTime is the last time that fits in the timestamp bits
+func TestGenerator_OverflowTime(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want time.Time
+	}{
+		{name: "default", want: time.Date(2163, 7, 14, 6, 35, 11, 103e6, time.UTC)},
+		{
+			name: "45 timestamp bits",
+			opts: []Option{WithEpoch(time.UnixMilli(0)), WithLayout(45, 10, 8)},
+			want: time.UnixMilli(1<<45 - 1),
+		},
+		{
+			name: "microseconds",
+			opts: []Option{WithEpoch(time.Unix(1700000000, 0)), WithMicrosecondResolution(), WithLayout(55, 2, 6)},
+			want: time.UnixMicro(1700000000e6 + 1<<55 - 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(1, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.OverflowTime(); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got.UTC())
+			}
+		})
+	}
+}
+
+// TestGenerator_RemainingLifespan tests RemainingLifespan before, at and after the exhaustion of the timestamp bits
+func TestGenerator_RemainingLifespan(t *testing.T) {
+	generator, err := NewGenerator(1, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	tests := []struct {
+		name string
+		now  uint64
+		want time.Duration
+	}{
+		{name: "epoch", now: 0, want: 1 << 42 * time.Millisecond},
+		{name: "last millisecond", now: 1<<42 - 1, want: time.Millisecond},
+		{name: "exhausted", now: 1 << 42, want: 0},
+		{name: "after exhaustion", now: 1<<42 + 5, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator.SetTimeFunc(func() uint64 {
+				return tt.now
+			})
+			if got := generator.RemainingLifespan(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	generator, err = NewGenerator(1, WithEpoch(time.UnixMilli(0)), WithTimeUnit(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.RemainingLifespan(); got != math.MaxInt64 {
+		t.Errorf("expected %v, got %v", time.Duration(math.MaxInt64), got)
+	}
+}
+
+// TestGenerator_NextID_EpochExhausted tests that the last millisecond of the epoch is usable and that the generator
+// returns ErrTimestampOverflow instead of wrapping the timestamp after it, with and without drift
+func TestGenerator_NextID_EpochExhausted(t *testing.T) {
+	for _, opts := range [][]Option{{}, {WithDriftNoWait(time.Second)}, {WithVersionBit(1), WithDriftNoWait(time.Second)}} {
+		generator, err := NewGenerator(378, append(opts, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(21))...)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		last := generator.layout.timestampMask()
+		generator.SetTimeFunc(func() uint64 {
+			return last
+		})
+		for sequence := uint64(0); sequence <= generator.sequenceMask; sequence++ {
+			id, err := generator.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.DecodeID(id); got.Timestamp != last || got.Sequence != sequence {
+				t.Errorf("expected timestamp %v and sequence %v, got %v", last, sequence, got)
+			}
+		}
+		if _, err = generator.NextID(); !errors.Is(err, ErrOutOfSequence) && !errors.Is(err, ErrTimestampOverflow) {
+			t.Errorf("expected ErrOutOfSequence or ErrTimestampOverflow, got %v", err)
+		}
+		if generator.drift && !errors.Is(err, ErrTimestampOverflow) {
+			t.Errorf("expected ErrTimestampOverflow when drifting past the last millisecond, got %v", err)
+		}
+		generator.SetTimeFunc(func() uint64 {
+			return last + 1
+		})
+		if _, err = generator.NextID(); !errors.Is(err, ErrTimestampOverflow) {
+			t.Errorf("expected ErrTimestampOverflow, got %v", err)
+		}
+		if _, err = generator.BlockingNextID(context.Background()); !errors.Is(err, ErrTimestampOverflow) {
+			t.Errorf("expected ErrTimestampOverflow from BlockingNextID, got %v", err)
+		}
+	}
+}
+
+// TestGenerator_BlockingNextIDDone tests that closing the done channel aborts BlockingNextIDDone while it blocks
+func TestGenerator_BlockingNextIDDone(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	done := make(chan struct{})
+	var sleeps int
+	generator.sleepFunc = func() {
+		sleeps++
+		if sleeps == 3 {
+			close(done)
+		}
+	}
+
+	for i := uint64(0); i <= generator.sequenceMask; i++ {
+		if _, err = generator.BlockingNextIDDone(done); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if _, err = generator.BlockingNextIDDone(done); !errors.Is(err, ErrCanceled) {
+		t.Errorf("expected ErrCanceled, got %v", err)
+	}
+	if sleeps != 3 {
+		t.Errorf("expected 3 sleeps, got %v", sleeps)
+	}
+}
+
+// TestNewGenerator_SentinelErrors tests that the errors of NewGenerator and NextID can be told apart with errors.Is
+func TestNewGenerator_SentinelErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		machineID uint64
+		opts      []Option
+		want      []error
+		message   string
+	}{
+		{
+			name:      "machine ID too large",
+			machineID: 1024,
+			want:      []error{ErrMachineIDTooLarge},
+			message:   "machine ID is too large: 1024 does not fit in 10 machine ID bits, the maximum is 1023",
+		},
+		{
+			name:    "machine ID bits too small",
+			opts:    []Option{WithMachineIDBits(0)},
+			want:    []error{ErrMachineBitsTooSmall, ErrInvalidMachineIDBits},
+			message: "invalid machine ID bits: machine ID bits is too small",
+		},
+		{
+			name:    "machine ID bits too large",
+			opts:    []Option{WithMachineIDBits(22)},
+			want:    []error{ErrMachineBitsTooLarge, ErrInvalidMachineIDBits},
+			message: "invalid machine ID bits: machine ID bits is too large: 22 bits, at most 21 leave room for the sequence",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenerator(tt.machineID, tt.opts...)
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("expected %v, got %v", want, err)
+					return
+				}
+			}
+			if errors.Is(err, ErrSequenceExhausted) {
+				t.Errorf("expected a configuration error, got %v", err)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("expected %v, got %v", tt.message, err)
+			}
+		})
+	}
+
+	generator, err := NewGenerator(378, WithMachineIDBits(21))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return defaultEpoch + 1
+	})
+	for i := uint64(0); i <= generator.sequenceMask; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrSequenceExhausted) {
+		t.Errorf("expected ErrSequenceExhausted, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package snowflake
+
+import "sync/atomic"
+
+// history is a ring buffer of the most recently generated IDs
+type history struct {
+	next atomic.Uint64
+	ids  []atomic.Uint64
+}
+
+// record adds an ID to the history, overwriting the oldest ID when the history is full
+func (h *history) record(id ID) {
+	i := h.next.Add(1) - 1
+	h.ids[i%uint64(len(h.ids))].Store(uint64(id))
+}
+
+// WithHistory records the last n generated IDs, which can be read with History, for example to dump recent activity
+// when the process panics. Recording costs two atomic operations per ID, so it is disabled by default
+func WithHistory(n int) Option {
+	return func(generator *Generator) {
+		if n > 0 {
+			generator.history = &history{ids: make([]atomic.Uint64, n)}
+		}
+	}
+}
+
+// History returns the last generated IDs, from the oldest to the newest
+// Returns nil if WithHistory is not enabled. While other goroutines generate IDs the history is a snapshot that may
+// miss IDs that are being recorded
+func (g *Generator) History() []ID {
+	if g.history == nil {
+		return nil
+	}
+	next := g.history.next.Load()
+	size := uint64(len(g.history.ids))
+	count := next
+	if count > size {
+		count = size
+	}
+	ids := make([]ID, count)
+	for i := range ids {
+		ids[i] = ID(g.history.ids[(next-count+uint64(i))%size].Load())
+	}
+	return ids
+}
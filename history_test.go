@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestGenerator_History tests that History returns the last generated IDs from the oldest to the newest
+func TestGenerator_History(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		count int
+	}{
+		{name: "empty", size: 3, count: 0},
+		{name: "partially filled", size: 3, count: 2},
+		{name: "full", size: 3, count: 3},
+		{name: "wrapped", size: 3, count: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithHistory(tt.size))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			generator.SetTimeFunc(func() uint64 {
+				return 367597485448
+			})
+			want := []ID{}
+			for i := 0; i < tt.count; i++ {
+				id, err := generator.NextID()
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				want = append(want, id)
+			}
+			if len(want) > tt.size {
+				want = want[len(want)-tt.size:]
+			}
+			if _, err = generator.PeekNextID(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.History(); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+// TestGenerator_History_Disabled tests that History returns nil without WithHistory
+func TestGenerator_History_Disabled(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.History(); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+// TestGenerator_History_Concurrent tests that the history can be read while other goroutines generate IDs
+func TestGenerator_History_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(378, WithHistory(16))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if _, err := generator.NextID(); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				generator.History()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := generator.History(); len(got) != 16 {
+		t.Errorf("expected 16 IDs, got %v", len(got))
+	}
+}
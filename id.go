@@ -1,20 +1,38 @@
 package snowflake
 
 import (
+	stdbase32 "encoding/base32"
+	stdbase64 "encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"github.com/crosscode-nl/snowflake/internal/codecs/base64"
 	"github.com/crosscode-nl/snowflake/internal/codecs/base64/influx"
 	"github.com/crosscode-nl/snowflake/internal/codecs/hex"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidBinaryLength is returned when a binary representation of an ID is not exactly 8 bytes
+	ErrInvalidBinaryLength = errors.New("binary ID must be 8 bytes")
+	// ErrInvalidID is returned when a string is not a valid ID
+	ErrInvalidID = errors.New("invalid ID")
 )
 
 // ID is a snowflake ID
+// Its underlying type is uint64, so IDs can be compared with <, sorted with Sort and used with generic functions
+// constrained to ordered types, such as slices.Sort and the built-in min and max of Go 1.21. With the default field
+// order this sorts IDs by time, use uint64(id) for the underlying value
 type ID uint64
 
 type Alphabet func() [64]byte
 type AlphabetLookup func() map[byte]uint64
 
-// String returns a string representation of the snowflake ID
+// String returns the decimal representation of the snowflake ID, which matches MarshalJSON and MarshalText
+// Use Influx64String for the compact form and Debug for the decoded components
 func (id ID) String() string {
-	return id.Influx64String()
+	return strconv.FormatUint(uint64(id), 10)
 }
 
 // LowerHexString returns a lower case hex string of the snowflake ID
@@ -59,11 +77,55 @@ func (id ID) Influx64StringCustom(alphabet Alphabet) string {
 	return string(b[:])
 }
 
-// IDFromString returns a snowflake ID from a string
+// IDFromString returns a snowflake ID from an Influx64 string, as returned by Influx64String
+// Use ParseID for the decimal form returned by String
 func IDFromString(s string) ID {
 	return IDFromInflux64String(s)
 }
 
+// DescendingBytes returns the big-endian bytes of the bitwise complement of the snowflake ID
+// Lexicographic order of the bytes is the reverse of the numeric order of the IDs, so newer IDs sort first
+// The bytes are computed as the big-endian encoding of 0xFFFFFFFFFFFFFFFF - id, which equals ^id
+func (id ID) DescendingBytes() [8]byte {
+	return (^id).SortableBytes()
+}
+
+// IDFromDescendingBytes returns a snowflake ID from bytes as returned by DescendingBytes
+func IDFromDescendingBytes(b [8]byte) ID {
+	return ^IDFromSortableBytes(b)
+}
+
+// ParseID returns a snowflake ID from a decimal string
+// Returns an error if the string is not a decimal number or does not fit in 64 bits
+func ParseID(s string) (ID, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	return ID(n), nil
+}
+
+// ParseIDFast returns a snowflake ID from decimal ASCII digits, without converting them to a string
+// It accepts exactly the input ParseID accepts, but is faster for parsing large numbers of IDs
+// Returns an error if the input contains a non-digit byte, is empty or does not fit in 64 bits
+func ParseIDFast(b []byte) (ID, error) {
+	if len(b) == 0 {
+		return 0, fmt.Errorf("%w: empty input", ErrInvalidID)
+	}
+	var n uint64
+	for _, c := range b {
+		d := uint64(c - '0')
+		if d > 9 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidID, b)
+		}
+		if n > (1<<64-1-d)/10 {
+			return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidID, b)
+		}
+		n = n*10 + d
+	}
+	return ID(n), nil
+}
+
 // IDFromLowerHexString returns a snowflake ID from a lower case hex string
 func IDFromLowerHexString(s string) ID {
 	var b [16]byte
@@ -105,3 +167,127 @@ func IDFromInflux64StringCustom(s string, alphabetLookup AlphabetLookup) ID {
 	copy(b[:], s)
 	return ID(influx.Decode(&b, alphabetLookup))
 }
+
+// GobEncode encodes the snowflake ID as 8 big-endian bytes for encoding/gob
+func (id ID) GobEncode() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(id))
+	return b, nil
+}
+
+// GobDecode decodes a snowflake ID from 8 big-endian bytes for encoding/gob
+func (id *ID) GobDecode(b []byte) error {
+	if len(b) != 8 {
+		return ErrInvalidBinaryLength
+	}
+	*id = ID(binary.BigEndian.Uint64(b))
+	return nil
+}
+
+// MarshalBinary marshals the snowflake ID as 8 big-endian bytes, like GobEncode and SortableBytes
+// It implements encoding.BinaryMarshaler
+func (id ID) MarshalBinary() ([]byte, error) {
+	return id.GobEncode()
+}
+
+// UnmarshalBinary unmarshals the snowflake ID from 8 big-endian bytes
+// It implements encoding.BinaryUnmarshaler
+// Returns ErrInvalidBinaryLength if b is not exactly 8 bytes, the ID is unchanged then
+func (id *ID) UnmarshalBinary(b []byte) error {
+	return id.GobDecode(b)
+}
+
+// SortableBytes returns the big-endian bytes of the snowflake ID
+// Lexicographic order of the bytes equals the numeric order of the IDs, which makes them suitable as sorted keys
+func (id ID) SortableBytes() [8]byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+	return b
+}
+
+// IDFromSortableBytes returns a snowflake ID from big-endian bytes as returned by SortableBytes
+func IDFromSortableBytes(b [8]byte) ID {
+	return ID(binary.BigEndian.Uint64(b[:]))
+}
+
+// LittleEndianBytes returns the little-endian bytes of the snowflake ID, the least significant byte first
+// This is the wire format of systems that store IDs little-endian, use SortableBytes for the canonical big-endian form
+func (id ID) LittleEndianBytes() [8]byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(id))
+	return b
+}
+
+// DecodeLittleEndianBytes returns a snowflake ID from little-endian bytes, the least significant byte first
+// Returns ErrInvalidBinaryLength if b is not exactly 8 bytes
+func DecodeLittleEndianBytes(b []byte) (ID, error) {
+	if len(b) != 8 {
+		return 0, ErrInvalidBinaryLength
+	}
+	return ID(binary.LittleEndian.Uint64(b)), nil
+}
+
+// Base64URL returns the big-endian bytes of the snowflake ID in unpadded URL-safe base64, which is always 11 characters
+func (id ID) Base64URL() string {
+	b := id.SortableBytes()
+	return stdbase64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// ParseBase64URL returns a snowflake ID from unpadded URL-safe base64 as returned by Base64URL
+// Returns an error if the string is not 11 characters of canonical URL-safe base64
+func ParseBase64URL(s string) (ID, error) {
+	if len(s) != 11 {
+		return 0, fmt.Errorf("%w: %q must be 11 characters", ErrInvalidID, s)
+	}
+	var b [8]byte
+	if _, err := stdbase64.RawURLEncoding.Strict().Decode(b[:], []byte(s)); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	return IDFromSortableBytes(b), nil
+}
+
+// Base32 returns the big-endian bytes of the snowflake ID in unpadded standard base32 (RFC 4648), which is always 13
+// characters
+func (id ID) Base32() string {
+	b := id.SortableBytes()
+	return stdbase32.StdEncoding.WithPadding(stdbase32.NoPadding).EncodeToString(b[:])
+}
+
+// ParseBase32 returns a snowflake ID from unpadded standard base32 as returned by Base32, lower case is accepted
+// Returns an error if the string is not 13 characters of canonical base32
+func ParseBase32(s string) (ID, error) {
+	if len(s) != 13 {
+		return 0, fmt.Errorf("%w: %q must be 13 characters", ErrInvalidID, s)
+	}
+	var b [8]byte
+	s = strings.ToUpper(s)
+	if _, err := stdbase32.StdEncoding.WithPadding(stdbase32.NoPadding).Decode(b[:], []byte(s)); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	id := IDFromSortableBytes(b)
+	// The last character has an unused bit, which must be zero so every ID has exactly one encoding
+	if id.Base32() != s {
+		return 0, fmt.Errorf("%w: %q is not canonical", ErrInvalidID, s)
+	}
+	return id, nil
+}
+
+// Base62 returns the snowflake ID in base62 with the alphabet [0-9A-Za-z], which is at most 11 characters
+// The ID is converted with the most significant digit first and without leading zeros, so IDs of the same length sort
+// like their values
+func (id ID) Base62() string {
+	return base62.Encode(id)
+}
+
+// ParseBase62 returns a snowflake ID from a base62 string as returned by Base62
+// Returns an error if the string is empty, contains characters outside [0-9A-Za-z] or does not fit in 64 bits
+func ParseBase62(s string) (ID, error) {
+	return base62.Decode(s)
+}
+
+// FromSignedInt64 returns the snowflake ID with the same bits as the two's complement int64
+// Producers that store IDs as int64 turn IDs with the most significant bit set into negative numbers, for example
+// -1 becomes 0xFFFFFFFFFFFFFFFF. The bits are reinterpreted without any conversion, so the ID decodes as generated
+func FromSignedInt64(i int64) ID {
+	return ID(uint64(i))
+}
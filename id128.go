@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"github.com/crosscode-nl/snowflake/internal/codecs/hex"
+	"strconv"
+)
+
+// ID128 is a 128-bit ID with a snowflake ID in the high 64 bits and random bits in the low 64 bits
+// The snowflake ID is the most significant half, so ID128 values sort by time like UUIDv7 while the random half makes
+// them unique without coordinating machine IDs. String returns the hyphenated form of a UUID, but ID128 has no UUID
+// version or variant bits, as they would overwrite bits of the snowflake ID
+type ID128 struct {
+	// Hi is the snowflake ID
+	Hi uint64
+	// Lo is the random half
+	Lo uint64
+}
+
+// Snowflake returns the snowflake ID in the high 64 bits
+func (id ID128) Snowflake() ID {
+	return ID(id.Hi)
+}
+
+// Bytes returns the 16 big-endian bytes of the ID, the snowflake ID first
+func (id ID128) Bytes() [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], id.Hi)
+	binary.BigEndian.PutUint64(b[8:], id.Lo)
+	return b
+}
+
+// String returns the ID as 32 lower case hex digits in the hyphenated 8-4-4-4-12 form of a UUID
+func (id ID128) String() string {
+	var hi, lo [16]byte
+	hex.Encode(&hi, id.Hi, hex.Lower)
+	hex.Encode(&lo, id.Lo, hex.Lower)
+	b := make([]byte, 0, 36)
+	b = append(b, hi[:8]...)
+	b = append(b, '-')
+	b = append(b, hi[8:12]...)
+	b = append(b, '-')
+	b = append(b, hi[12:]...)
+	b = append(b, '-')
+	b = append(b, lo[:4]...)
+	b = append(b, '-')
+	return string(append(b, lo[4:]...))
+}
+
+// ParseID128 parses an ID in the hyphenated form returned by String, upper case hex digits are accepted too
+// Returns an error wrapping ErrInvalidID if s is not 36 characters with hyphens at the positions of a UUID and hex
+// digits elsewhere
+func ParseID128(s string) (ID128, error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return ID128{}, fmt.Errorf("%w: %q is not in the 8-4-4-4-12 form", ErrInvalidID, s)
+	}
+	hi, err := strconv.ParseUint(s[:8]+s[9:13]+s[14:18], 16, 64)
+	if err != nil {
+		return ID128{}, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	lo, err := strconv.ParseUint(s[19:23]+s[24:], 16, 64)
+	if err != nil {
+		return ID128{}, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	return ID128{Hi: hi, Lo: lo}, nil
+}
+
+// Generator128 generates ID128 values with a snowflake generator for the high half and crypto/rand for the low half
+// All methods of the snowflake generator are available, for example DecodeID(id.Snowflake())
+type Generator128 struct {
+	*Generator
+}
+
+// NewGenerator128 creates a generator of ID128 values with a snowflake generator created by NewGenerator
+// Returns the error of NewGenerator
+func NewGenerator128(machineID uint64, opts ...Option) (*Generator128, error) {
+	g, err := NewGenerator(machineID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Generator128{Generator: g}, nil
+}
+
+// NextID128 generates a new ID128 from a snowflake ID of NextID and 64 random bits
+// Returns the error of NextID or the error of reading the random bits
+func (g *Generator128) NextID128() (ID128, error) {
+	id, err := g.NextID()
+	if err != nil {
+		return ID128{}, err
+	}
+	return newID128(id)
+}
+
+// BlockingNextID128 generates a new ID128 like NextID128 with a snowflake ID of BlockingNextID
+// Returns the error of BlockingNextID or the error of reading the random bits
+func (g *Generator128) BlockingNextID128(ctx context.Context) (ID128, error) {
+	id, err := g.BlockingNextID(ctx)
+	if err != nil {
+		return ID128{}, err
+	}
+	return newID128(id)
+}
+
+// newID128 combines the snowflake ID with 64 random bits
+func newID128(id ID) (ID128, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ID128{}, err
+	}
+	return ID128{Hi: uint64(id), Lo: binary.BigEndian.Uint64(b[:])}, nil
+}
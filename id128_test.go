@@ -0,0 +1,130 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestID128_String tests the hyphenated form of ID128 and that ParseID128 parses it back
+func TestID128_String(t *testing.T) {
+	tests := []struct {
+		name string
+		id   ID128
+		want string
+	}{
+		{name: "zero", id: ID128{}, want: "00000000-0000-0000-0000-000000000000"},
+		{
+			name: "Twitter test vector",
+			id:   ID128{Hi: 1541815603606036480, Lo: 0x0123456789abcdef},
+			want: "1565a11f-6217-a000-0123-456789abcdef",
+		},
+		{name: "max", id: ID128{Hi: 1<<64 - 1, Lo: 1<<64 - 1}, want: "ffffffff-ffff-ffff-ffff-ffffffffffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.String(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+				return
+			}
+			got, err := ParseID128(tt.want)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got != tt.id {
+				t.Errorf("expected %v, got %v", tt.id, got)
+			}
+		})
+	}
+}
+
+// TestParseID128 tests that ParseID128 accepts upper case digits and rejects malformed input
+func TestParseID128(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    ID128
+		wantErr error
+	}{
+		{name: "upper case", s: "1565A11F-6217-A000-0123-456789ABCDEF", want: ID128{Hi: 1541815603606036480, Lo: 0x0123456789abcdef}},
+		{name: "empty", s: "", wantErr: ErrInvalidID},
+		{name: "without hyphens", s: "1565a11f6217a0000123456789abcdef", wantErr: ErrInvalidID},
+		{name: "misplaced hyphen", s: "1565a11-f6217-a000-0123-456789abcdef", wantErr: ErrInvalidID},
+		{name: "not hex in the high half", s: "1565a11f-6217-a00g-0123-456789abcdef", wantErr: ErrInvalidID},
+		{name: "not hex in the low half", s: "1565a11f-6217-a000-0123-456789abcdeg", wantErr: ErrInvalidID},
+		{name: "sign", s: "+565a11f-6217-a000-0123-456789abcdef", wantErr: ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseID128(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestID128_Bytes tests that the bytes of ID128 are big-endian with the snowflake ID first
+func TestID128_Bytes(t *testing.T) {
+	got := ID128{Hi: 0x0102030405060708, Lo: 0x090a0b0c0d0e0f10}.Bytes()
+	want := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	if got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestGenerator128_NextID128 tests that ID128 values hold the snowflake ID in the high half and sort by time
+func TestGenerator128_NextID128(t *testing.T) {
+	generator, err := NewGenerator128(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	var previous ID128
+	los := map[uint64]struct{}{}
+	for i := 0; i < 100; i++ {
+		now += uint64(i % 2)
+		id, err := generator.NextID128()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if got := generator.DecodeID(id.Snowflake()); got.Timestamp != now || got.MachineID != 378 {
+			t.Errorf("expected timestamp %v and machine ID 378, got %v", now, got)
+			return
+		}
+		if id.Hi <= previous.Hi {
+			t.Errorf("expected %v to sort after %v", id, previous)
+			return
+		}
+		los[id.Lo] = struct{}{}
+		previous = id
+	}
+	if len(los) != 100 {
+		t.Errorf("expected 100 different random halves, got %v", len(los))
+	}
+
+	id, err := generator.BlockingNextID128(context.Background())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if id.Hi <= previous.Hi {
+		t.Errorf("expected %v to sort after %v", id, previous)
+	}
+
+	if _, err = NewGenerator128(1024); !errors.Is(err, ErrMachineIDTooLarge) {
+		t.Errorf("expected ErrMachineIDTooLarge, got %v", err)
+	}
+}
@@ -1,9 +1,17 @@
 package snowflake
 
 import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
 	"fmt"
 	"github.com/crosscode-nl/snowflake/internal/codecs/base64"
 	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -204,9 +212,9 @@ func ExampleID_String() {
 	id = ID(math.MaxUint64)
 	fmt.Println(id.String())
 	// Output:
-	// 00000000001
-	// A00h0xA0ZHI
-	// F~~~~~~~~~~
+	// 1
+	// 11529408624707384402
+	// 18446744073709551615
 }
 
 // ExampleIDFromString is an example of the IDFromString function
@@ -249,3 +257,501 @@ func ExampleIDFromUpperHexString() {
 	// 11529408624707384402
 	// 18446744073709551615
 }
+
+// TestID_Gob tests the gob round-trip of a struct containing an ID
+func TestID_Gob(t *testing.T) {
+	type cached struct {
+		Name string
+		ID   ID
+	}
+	tests := []struct {
+		name string
+		id   ID
+	}{
+		{name: "zero", id: 0},
+		{name: "Twitter test vector", id: 1541815603606036480},
+		{name: "max", id: math.MaxUint64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(cached{Name: tt.name, ID: tt.id}); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			var got cached
+			if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got.ID != tt.id || got.Name != tt.name {
+				t.Errorf("got %v, want %v", got, cached{Name: tt.name, ID: tt.id})
+			}
+		})
+	}
+}
+
+// TestID_GobDecode_InvalidLength tests that GobDecode rejects input that is not 8 bytes
+func TestID_GobDecode_InvalidLength(t *testing.T) {
+	var id ID
+	if err := id.GobDecode([]byte{1, 2, 3}); !errors.Is(err, ErrInvalidBinaryLength) {
+		t.Errorf("expected ErrInvalidBinaryLength, got %v", err)
+	}
+}
+
+// TestID_MarshalBinary tests that IDs over the full value range survive a binary round trip as 8 big-endian bytes
+func TestID_MarshalBinary(t *testing.T) {
+	ids := []ID{0, math.MaxUint64, 1541815603606036480}
+	for bit := 0; bit < 64; bit++ {
+		ids = append(ids, 1<<bit, 1<<bit-1)
+	}
+	for _, id := range ids {
+		b, err := id.MarshalBinary()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if want := id.SortableBytes(); !bytes.Equal(b, want[:]) {
+			t.Errorf("expected %v, got %v", want, b)
+			return
+		}
+		var got ID
+		if err = got.UnmarshalBinary(b); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if got != id {
+			t.Errorf("expected %v, got %v", uint64(id), uint64(got))
+			return
+		}
+	}
+}
+
+// TestID_UnmarshalBinary_InvalidLength tests that UnmarshalBinary rejects input that is not 8 bytes
+func TestID_UnmarshalBinary_InvalidLength(t *testing.T) {
+	for _, length := range []int{0, 1, 7, 9, 16} {
+		id := ID(1)
+		if err := id.UnmarshalBinary(make([]byte, length)); !errors.Is(err, ErrInvalidBinaryLength) {
+			t.Errorf("expected ErrInvalidBinaryLength for length %d, got %v", length, err)
+		}
+		if id != 1 {
+			t.Errorf("expected the ID to be unchanged, got %v", uint64(id))
+		}
+	}
+}
+
+// parsers are the string decoders of ID and their matching encoders
+var parsers = []struct {
+	name   string
+	parse  func(string) ID
+	format func(ID) string
+}{
+	{name: "LowerHex", parse: IDFromLowerHexString, format: ID.LowerHexString},
+	{name: "UpperHex", parse: IDFromUpperHexString, format: ID.UpperHexString},
+	{name: "Base64", parse: IDFromBase64String, format: ID.Base64String},
+	{name: "Influx64", parse: IDFromInflux64String, format: ID.Influx64String},
+}
+
+// checkedParsers are the string decoders that reject invalid input with an error, with their matching encoder
+var checkedParsers = []struct {
+	name   string
+	parse  func(string) (ID, error)
+	format func(ID) string
+}{
+	{name: "Decimal", parse: ParseID, format: ID.String},
+	{name: "Base62", parse: ParseBase62, format: ID.Base62},
+}
+
+// FuzzParse tests that the string decoders do not panic on arbitrary input and that parsed IDs round-trip through
+// the matching encoder
+func FuzzParse(f *testing.F) {
+	for _, id := range []ID{0, 1, 1541815603606036480, math.MaxUint64} {
+		for _, p := range parsers {
+			f.Add(p.format(id))
+		}
+		for _, p := range checkedParsers {
+			f.Add(p.format(id))
+		}
+	}
+	f.Add("")
+	f.Add("000000000000000000001")
+	f.Add("~~~~~~~~~~~~")
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, p := range parsers {
+			id := p.parse(s)
+			formatted := p.format(id)
+			if got := p.parse(formatted); got != id {
+				t.Errorf("%s: parse(%q) = %v, want %v", p.name, formatted, uint64(got), uint64(id))
+			}
+		}
+		for _, p := range checkedParsers {
+			id, err := p.parse(s)
+			if err != nil {
+				if !errors.Is(err, ErrInvalidID) {
+					t.Errorf("%s: parse(%q) error = %v, want %v", p.name, s, err, ErrInvalidID)
+				}
+				continue
+			}
+			formatted := p.format(id)
+			if got, err := p.parse(formatted); err != nil || got != id {
+				t.Errorf("%s: parse(%q) = %v, %v, want %v", p.name, formatted, uint64(got), err, uint64(id))
+			}
+		}
+	})
+}
+
+// FuzzFormat tests that every ID round-trips through the string encoders and decoders
+func FuzzFormat(f *testing.F) {
+	f.Add(uint64(0))
+	f.Add(uint64(1541815603606036480))
+	f.Add(uint64(math.MaxUint64))
+	f.Fuzz(func(t *testing.T, n uint64) {
+		for _, p := range parsers {
+			if got := p.parse(p.format(ID(n))); got != ID(n) {
+				t.Errorf("%s: parse(format(%v)) = %v", p.name, n, uint64(got))
+			}
+		}
+	})
+}
+
+// TestID_SortableBytes tests that SortableBytes preserves the order of sequential IDs
+func TestID_SortableBytes(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var previous [8]byte
+	for i := 0; i < 10000; i++ {
+		id, err := generator.BlockingNextID(context.TODO())
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		b := id.SortableBytes()
+		if bytes.Compare(previous[:], b[:]) >= 0 {
+			t.Errorf("expected %x to sort after %x", b, previous)
+			return
+		}
+		if got := IDFromSortableBytes(b); got != id {
+			t.Errorf("expected %v, got %v", id, got)
+		}
+		previous = b
+	}
+}
+
+// ExampleID_SortableBytes is an example of the ID SortableBytes method
+func ExampleID_SortableBytes() {
+	id := ID(0xA000B00F0A023452)
+	fmt.Printf("%x\n", id.SortableBytes())
+	fmt.Println(uint64(IDFromSortableBytes(id.SortableBytes())))
+	// Output:
+	// a000b00f0a023452
+	// 11529408624707384402
+}
+
+// TestParseID tests the ParseID function
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    ID
+		wantErr error
+	}{
+		{name: "zero", s: "0", want: 0},
+		{name: "Twitter test vector", s: "1541815603606036480", want: 1541815603606036480},
+		{name: "max", s: "18446744073709551615", want: math.MaxUint64},
+		{name: "overflow", s: "18446744073709551616", wantErr: ErrInvalidID},
+		{name: "not a number", s: "002OwE4W100", wantErr: ErrInvalidID},
+		{name: "empty", s: "", wantErr: ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseID(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(got))
+			}
+		})
+	}
+}
+
+// FuzzParseIDFast tests that ParseIDFast accepts exactly the input strconv.ParseUint accepts
+func FuzzParseIDFast(f *testing.F) {
+	for _, s := range []string{"0", "00001", "1541815603606036480", "18446744073709551615", "18446744073709551616", "", "+1", "-1", "1_0", "99999999999999999999"} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		want, wantErr := strconv.ParseUint(string(b), 10, 64)
+		got, err := ParseIDFast(b)
+		if (err == nil) != (wantErr == nil) {
+			t.Errorf("ParseIDFast(%q) error = %v, strconv error = %v", b, err, wantErr)
+			return
+		}
+		if err == nil && uint64(got) != want {
+			t.Errorf("ParseIDFast(%q) = %v, want %v", b, uint64(got), want)
+		}
+	})
+}
+
+// BenchmarkParseIDFast benchmarks the ParseIDFast function
+func BenchmarkParseIDFast(b *testing.B) {
+	s := []byte("1541815603606036480")
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseIDFast(s)
+	}
+}
+
+// BenchmarkParseUint benchmarks strconv.ParseUint as a baseline for ParseIDFast
+func BenchmarkParseUint(b *testing.B) {
+	s := []byte("1541815603606036480")
+	for i := 0; i < b.N; i++ {
+		_, _ = strconv.ParseUint(string(s), 10, 64)
+	}
+}
+
+// TestID_DescendingBytes tests that DescendingBytes reverses the order of sequential IDs
+func TestID_DescendingBytes(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	previous := [8]byte{}
+	for i := 0; i < 10000; i++ {
+		id, err := generator.BlockingNextID(context.TODO())
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		b := id.DescendingBytes()
+		if i > 0 && bytes.Compare(previous[:], b[:]) <= 0 {
+			t.Errorf("expected %x to sort before %x", b, previous)
+			return
+		}
+		if got := IDFromDescendingBytes(b); got != id {
+			t.Errorf("expected %v, got %v", id, got)
+		}
+		previous = b
+	}
+}
+
+// ExampleID_DescendingBytes is an example of the ID DescendingBytes method
+func ExampleID_DescendingBytes() {
+	id := ID(0xA000B00F0A023452)
+	fmt.Printf("%x\n", id.DescendingBytes())
+	fmt.Println(uint64(IDFromDescendingBytes(id.DescendingBytes())))
+	// Output:
+	// 5fff4ff0f5fdcbad
+	// 11529408624707384402
+}
+
+// TestDecodeLittleEndianBytes tests the DecodeLittleEndianBytes function and the LittleEndianBytes method of the ID type
+func TestDecodeLittleEndianBytes(t *testing.T) {
+	id := ID(0xA000B00F0A023452)
+	b := id.LittleEndianBytes()
+	if want := [8]byte{0x52, 0x34, 0x02, 0x0A, 0x0F, 0xB0, 0x00, 0xA0}; b != want {
+		t.Errorf("expected %x, got %x", want, b)
+	}
+	got, err := DecodeLittleEndianBytes(b[:])
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got != id {
+		t.Errorf("expected %v, got %v", uint64(id), uint64(got))
+	}
+	for _, length := range []int{0, 7, 9} {
+		if _, err = DecodeLittleEndianBytes(make([]byte, length)); !errors.Is(err, ErrInvalidBinaryLength) {
+			t.Errorf("expected ErrInvalidBinaryLength for length %d, got %v", length, err)
+		}
+	}
+}
+
+// TestParseBase64URL tests the ParseBase64URL function and the Base64URL method of the ID type
+func TestParseBase64URL(t *testing.T) {
+	tests := []struct {
+		id   ID
+		want string
+	}{
+		{id: 0, want: "AAAAAAAAAAA"},
+		{id: 1541815603606036480, want: "FWWhH2IXoAA"},
+		{id: math.MaxUint64, want: "__________8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.id.Base64URL(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+				return
+			}
+			got, err := ParseBase64URL(tt.want)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got != tt.id {
+				t.Errorf("expected %v, got %v", uint64(tt.id), uint64(got))
+			}
+		})
+	}
+	for _, s := range []string{"", "FWWhH2IXoA", "FWWhH2IXoAAA", "FWWhH2IXoA+", "FWWhH2IXoA=", "__________9"} {
+		if _, err := ParseBase64URL(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected ErrInvalidID for %q, got %v", s, err)
+		}
+	}
+}
+
+// TestParseBase32 tests the ParseBase32 function and the Base32 method of the ID type
+func TestParseBase32(t *testing.T) {
+	tests := []struct {
+		id   ID
+		want string
+	}{
+		{id: 0, want: "AAAAAAAAAAAAA"},
+		{id: 1541815603606036480, want: "CVS2CH3CC6QAA"},
+		{id: math.MaxUint64, want: "7777777777776"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.id.Base32(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+				return
+			}
+			for _, s := range []string{tt.want, strings.ToLower(tt.want)} {
+				got, err := ParseBase32(s)
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				if got != tt.id {
+					t.Errorf("expected %v, got %v", uint64(tt.id), uint64(got))
+				}
+			}
+		})
+	}
+	for _, s := range []string{"", "CVS2CH3CC6QA", "CVS2CH3CC6QAAA", "CVS2CH3CC6QA1", "CVS2CH3CC6QA=", "7777777777777"} {
+		if _, err := ParseBase32(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected ErrInvalidID for %q, got %v", s, err)
+		}
+	}
+}
+
+// TestParseBase62 tests the ParseBase62 function and the Base62 method of the ID type
+func TestParseBase62(t *testing.T) {
+	tests := []struct {
+		id   ID
+		want string
+	}{
+		{id: 0, want: "0"},
+		{id: 61, want: "z"},
+		{id: 62, want: "10"},
+		{id: 1541815603606036480, want: "1ptWyK4WgZU"},
+		{id: math.MaxUint64, want: "LygHa16AHYF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.id.Base62(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+				return
+			}
+			got, err := ParseBase62(tt.want)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got != tt.id {
+				t.Errorf("expected %v, got %v", uint64(tt.id), uint64(got))
+			}
+		})
+	}
+	for _, s := range []string{"", "1ptWyK4WgZ-", "1ptWyK4WgZ=", "LygHa16AHYG", "zzzzzzzzzzzz"} {
+		if _, err := ParseBase62(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("expected ErrInvalidID for %q, got %v", s, err)
+		}
+	}
+}
+
+// BenchmarkID_Base62 benchmarks the Base62 method of the ID type
+func BenchmarkID_Base62(b *testing.B) {
+	id := ID(1541815603606036480)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.Base62()
+	}
+}
+
+// TestFromSignedInt64 tests that FromSignedInt64 reinterprets the bits of the int64
+func TestFromSignedInt64(t *testing.T) {
+	tests := []struct {
+		i    int64
+		want ID
+	}{
+		{i: 0, want: 0},
+		{i: 1541815603606036480, want: 1541815603606036480},
+		{i: math.MaxInt64, want: 1<<63 - 1},
+		{i: math.MinInt64, want: 1 << 63},
+		{i: -1, want: math.MaxUint64},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.FormatInt(tt.i, 10), func(t *testing.T) {
+			if got := FromSignedInt64(tt.i); got != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(got))
+			}
+		})
+	}
+	// An ID with the version bit set that wrapped negative decodes to its components
+	id := FromSignedInt64(-7681556433248739328)
+	want := DecodedID{ID: 1<<63 | 1541815603606036480, Version: 1, Timestamp: 367597485448, MachineID: 378}
+	if got := DecodeID(id, Layout{VersionBit: true, MachineIDBits: 10}); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// ordered is the constraint of ordered types, like cmp.Ordered
+type ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// maxOf returns the largest of the values, like the built-in max
+func maxOf[T ordered](first T, rest ...T) T {
+	m := first
+	for _, v := range rest {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+// minOf returns the smallest of the values, like the built-in min
+func minOf[T ordered](first T, rest ...T) T {
+	m := first
+	for _, v := range rest {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// TestID_Ordered tests that IDs can be sorted and used with generic functions for ordered types
+func TestID_Ordered(t *testing.T) {
+	ids := []ID{1541815603606036481, 0, math.MaxUint64, 1541815603606036480}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	want := []ID{0, 1541815603606036480, 1541815603606036481, math.MaxUint64}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected %v, got %v", want, ids)
+	}
+	if got := maxOf(ids[1], ids[2:]...); got != math.MaxUint64 {
+		t.Errorf("expected %v, got %v", uint64(math.MaxUint64), uint64(got))
+	}
+	if got := minOf(ids[1], ids[2:]...); got != 1541815603606036480 {
+		t.Errorf("expected 1541815603606036480, got %v", uint64(got))
+	}
+}
@@ -0,0 +1,49 @@
+package basex
+
+// Lookup maps a byte to its digit value plus one, zero means the byte is not a digit
+type Lookup [256]uint8
+
+// NewLookup returns the lookup table of an alphabet
+func NewLookup(alphabet string) Lookup {
+	var lookup Lookup
+	for i := 0; i < len(alphabet); i++ {
+		lookup[alphabet[i]] = uint8(i + 1)
+	}
+	return lookup
+}
+
+// Append appends the encoding of a number in the base of the alphabet to dst, most significant digit first
+func Append(dst []byte, n uint64, alphabet string) []byte {
+	var buf [64]byte
+	base := uint64(len(alphabet))
+	i := len(buf)
+	for {
+		i--
+		buf[i], n = alphabet[n%base], n/base
+		if n == 0 {
+			break
+		}
+	}
+	return append(dst, buf[i:]...)
+}
+
+// Decode decodes a string in the base of the lookup table into a number
+// Returns false if the string is empty, contains a byte that is not a digit or does not fit in 64 bits
+func Decode(s string, base uint64, lookup *Lookup) (uint64, bool) {
+	if len(s) == 0 {
+		return 0, false
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := uint64(lookup[s[i]])
+		if d == 0 {
+			return 0, false
+		}
+		d--
+		if n > (1<<64-1-d)/base {
+			return 0, false
+		}
+		n = n*base + d
+	}
+	return n, true
+}
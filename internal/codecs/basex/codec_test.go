@@ -0,0 +1,60 @@
+package basex
+
+import (
+	"math"
+	"testing"
+)
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    uint64
+		alphabet string
+		expected string
+	}{
+		{name: "Input: 0 base 2", input: 0, alphabet: "01", expected: "0"},
+		{name: "Input: 5 base 2", input: 5, alphabet: "01", expected: "101"},
+		{name: "Input: 255 base 16", input: 255, alphabet: "0123456789abcdef", expected: "ff"},
+		{name: "Input: 61 base 62", input: 61, alphabet: base62, expected: "z"},
+		{name: "Input: 62 base 62", input: 62, alphabet: base62, expected: "10"},
+		{name: "Input: max base 62", input: math.MaxUint64, alphabet: base62, expected: "LygHa16AHYF"},
+		{name: "Input: max base 2", input: math.MaxUint64, alphabet: "01", expected: "1111111111111111111111111111111111111111111111111111111111111111"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Append([]byte("x"), tt.input, tt.alphabet)); got != "x"+tt.expected {
+				t.Errorf("Append() = %v, want %v", got, "x"+tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	lookup := NewLookup(base62)
+	tests := []struct {
+		name     string
+		input    string
+		expected uint64
+		ok       bool
+	}{
+		{name: "Input: 0", input: "0", expected: 0, ok: true},
+		{name: "Input: z", input: "z", expected: 61, ok: true},
+		{name: "Input: 10", input: "10", expected: 62, ok: true},
+		{name: "Input: leading zeros", input: "00010", expected: 62, ok: true},
+		{name: "Input: max", input: "LygHa16AHYF", expected: math.MaxUint64, ok: true},
+		{name: "Input: max + 1", input: "LygHa16AHYG", ok: false},
+		{name: "Input: too long", input: "100000000000", ok: false},
+		{name: "Input: empty", input: "", ok: false},
+		{name: "Input: invalid digit", input: "1-2", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Decode(tt.input, 62, &lookup)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("Decode() = %v, %v, want %v, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
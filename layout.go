@@ -0,0 +1,260 @@
+package snowflake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldOrder is the order of the fields of an ID, from the most to the least significant bits
+type FieldOrder int
+
+const (
+	// OrderTimestampMachineSequence orders the fields as: version | timestamp | machine ID | shard | nonce | sequence
+	// This is the default order, IDs sort by time
+	OrderTimestampMachineSequence FieldOrder = iota
+	// OrderSequenceTimestampMachine orders the fields as: version | sequence | timestamp | machine ID | shard | nonce
+	// Placing the sequence in the most significant bits spreads consecutive IDs over the key space, which avoids write
+	// hotspots on monotonically increasing primary keys, but IDs no longer sort by time
+	OrderSequenceTimestampMachine
+	// OrderTimestampSequenceMachine orders the fields as: version | timestamp | sequence | machine ID | shard | nonce
+	// This is the order of systems that place the machine ID in the least significant bits. IDs sort by time, but
+	// within a millisecond they sort by sequence before machine ID
+	OrderTimestampSequenceMachine
+)
+
+// String returns the name of the field order
+func (o FieldOrder) String() string {
+	switch o {
+	case OrderTimestampMachineSequence:
+		return "timestamp-machine-sequence"
+	case OrderSequenceTimestampMachine:
+		return "sequence-timestamp-machine"
+	case OrderTimestampSequenceMachine:
+		return "timestamp-sequence-machine"
+	default:
+		return fmt.Sprintf("FieldOrder(%d)", int(o))
+	}
+}
+
+// Layout describes how the bits of a snowflake ID are allocated
+// The timestamp uses 42 bits, or 41 bits when the version bit is reserved, the machine ID, shard and nonce use the
+// configured number of the remaining 22 bits and the sequence uses the bits that remain
+// With TimestampBits the timestamp uses that many bits instead and the most significant bit is unused, or holds the
+// version, so the fields below it share 63 minus TimestampBits bits
+// Order defines the position of the fields, by default: version | timestamp | machine ID | shard | nonce | sequence
+type Layout struct {
+	// VersionBit reserves the most significant bit for a version flag
+	VersionBit bool
+	// MachineIDBits is the number of bits used for the machine ID
+	MachineIDBits uint64
+	// ShardBits is the number of bits used for the shard
+	ShardBits uint64
+	// NonceBits is the number of bits used for the per-process nonce
+	NonceBits uint64
+	// Order is the order of the fields
+	Order FieldOrder
+	// SingleNode allows zero machine ID bits for a single generator, the sequence then gets the machine ID bits
+	SingleNode bool
+	// TimestampBits is the number of bits used for the timestamp, zero means the default of 42 or 41 bits
+	TimestampBits uint64
+}
+
+// DefaultLayout returns the layout of a generator without options, which has 10 machine ID bits and 12 sequence bits
+func DefaultLayout() Layout {
+	return Layout{MachineIDBits: 10}
+}
+
+// validate returns an error if the layout is invalid
+func (l Layout) validate() error {
+	if l.Order < OrderTimestampMachineSequence || l.Order > OrderTimestampSequenceMachine {
+		return fmt.Errorf("%w: %v", ErrInvalidFieldOrder, l.Order)
+	}
+	if l.TimestampBits > 62 {
+		return ErrTimestampBitsTooLarge
+	}
+	if l.MachineIDBits < 1 && !l.SingleNode {
+		return ErrMachineBitsTooSmall
+	}
+	// At least one bit is left for the sequence
+	limit := l.fieldBits() - 1
+	if l.MachineIDBits > limit {
+		return fmt.Errorf("%w: %d bits, at most %d leave room for the sequence", ErrMachineBitsTooLarge,
+			l.MachineIDBits, limit)
+	}
+	if l.MachineIDBits+l.ShardBits > limit {
+		return ErrShardBitsTooLarge
+	}
+	if l.MachineIDBits+l.ShardBits+l.NonceBits > limit {
+		return ErrNonceBitsTooLarge
+	}
+	return nil
+}
+
+// versionBits returns the number of bits reserved for the version
+func (l Layout) versionBits() uint64 {
+	if l.VersionBit {
+		return 1
+	}
+	return 0
+}
+
+// timestampBits returns the number of bits used for the timestamp
+func (l Layout) timestampBits() uint64 {
+	if l.TimestampBits != 0 {
+		return l.TimestampBits
+	}
+	return 64 - timeShift - l.versionBits()
+}
+
+// fieldBits returns the number of bits shared by the machine ID, shard, nonce and sequence
+func (l Layout) fieldBits() uint64 {
+	if l.TimestampBits != 0 {
+		return 63 - l.TimestampBits
+	}
+	return timeShift
+}
+
+// unusedBits returns the number of bits that no field uses, which is the most significant bit when the timestamp bits
+// are set without the version bit
+func (l Layout) unusedBits() uint64 {
+	if l.TimestampBits != 0 && !l.VersionBit {
+		return 1
+	}
+	return 0
+}
+
+// stateShift returns the position of the timestamp in the state of a generator, which must leave room for the
+// sequence below it and for the timestamp above it
+func (l Layout) stateShift() uint64 {
+	if l.TimestampBits != 0 {
+		return 64 - l.TimestampBits
+	}
+	return timeShift
+}
+
+// sequenceBits returns the number of bits used for the sequence
+func (l Layout) sequenceBits() uint64 {
+	return l.fieldBits() - l.MachineIDBits - l.ShardBits - l.NonceBits
+}
+
+// timestampShift returns the position of the least significant timestamp bit
+func (l Layout) timestampShift() uint64 {
+	if l.Order == OrderTimestampSequenceMachine {
+		return l.sequenceShift() + l.sequenceBits()
+	}
+	return l.machineIDShift() + l.MachineIDBits
+}
+
+// timestampMask returns the mask of the timestamp after shifting it to the least significant bits
+func (l Layout) timestampMask() uint64 {
+	return 1<<l.timestampBits() - 1
+}
+
+// machineIDShift returns the position of the least significant machine ID bit
+func (l Layout) machineIDShift() uint64 {
+	return l.shardShift() + l.ShardBits
+}
+
+// machineIDMask returns the mask of the machine ID after shifting it to the least significant bits
+func (l Layout) machineIDMask() uint64 {
+	return 1<<l.MachineIDBits - 1
+}
+
+// shardShift returns the position of the least significant shard bit
+func (l Layout) shardShift() uint64 {
+	return l.nonceShift() + l.NonceBits
+}
+
+// shardMask returns the mask of the shard after shifting it to the least significant bits
+func (l Layout) shardMask() uint64 {
+	return 1<<l.ShardBits - 1
+}
+
+// nonceShift returns the position of the least significant nonce bit
+func (l Layout) nonceShift() uint64 {
+	if l.Order != OrderTimestampMachineSequence {
+		return 0
+	}
+	return l.sequenceBits()
+}
+
+// nonceMask returns the mask of the nonce after shifting it to the least significant bits
+func (l Layout) nonceMask() uint64 {
+	return 1<<l.NonceBits - 1
+}
+
+// sequenceShift returns the position of the least significant sequence bit
+func (l Layout) sequenceShift() uint64 {
+	switch l.Order {
+	case OrderSequenceTimestampMachine:
+		return l.timestampShift() + l.timestampBits()
+	case OrderTimestampSequenceMachine:
+		return l.machineIDShift() + l.MachineIDBits
+	default:
+		return 0
+	}
+}
+
+// sequenceMask returns the mask of the sequence after shifting it to the least significant bits
+func (l Layout) sequenceMask() uint64 {
+	return 1<<l.sequenceBits() - 1
+}
+
+// layoutField is a field of a layout, used to draw the layout diagram
+type layoutField struct {
+	symbol byte
+	name   string
+	shift  uint64
+	bits   uint64
+}
+
+// fields returns the fields of the layout from the most to the least significant bits, fields without bits are omitted
+func (l Layout) fields() []layoutField {
+	fields := []layoutField{
+		{symbol: 'v', name: "version", shift: 63, bits: l.versionBits()},
+		{symbol: '-', name: "unused", shift: 63, bits: l.unusedBits()},
+		{symbol: 't', name: "timestamp", shift: l.timestampShift(), bits: l.timestampBits()},
+		{symbol: 'm', name: "machine ID", shift: l.machineIDShift(), bits: l.MachineIDBits},
+		{symbol: 'h', name: "shard", shift: l.shardShift(), bits: l.ShardBits},
+		{symbol: 'n', name: "nonce", shift: l.nonceShift(), bits: l.NonceBits},
+		{symbol: 's', name: "sequence", shift: l.sequenceShift(), bits: l.sequenceBits()},
+	}
+	present := fields[:0]
+	for _, f := range fields {
+		if f.bits > 0 {
+			present = append(present, f)
+		}
+	}
+	sort.Slice(present, func(i, j int) bool {
+		return present[i].shift > present[j].shift
+	})
+	return present
+}
+
+// diagram returns an ASCII diagram of the layout, with one character per bit and a legend with the bit ranges
+func (l Layout) diagram() string {
+	fields := l.fields()
+	var b strings.Builder
+	b.WriteString("63" + strings.Repeat(" ", 61) + "0\n")
+	for _, f := range fields {
+		b.WriteString(strings.Repeat(string(f.symbol), int(f.bits)))
+	}
+	b.WriteString("\n")
+	for _, f := range fields {
+		fmt.Fprintf(&b, "%c %-10s %2d bits %2d-%d\n", f.symbol, f.name, f.bits, f.shift+f.bits-1, f.shift)
+	}
+	return b.String()
+}
+
+// LayoutDiagram returns an ASCII diagram of the bit layout of the IDs generated by the generator
+// The first line marks the most and least significant bit, the second line has one character per bit and the legend
+// lists the width and bit range of every field
+func (g *Generator) LayoutDiagram() string {
+	return g.layout.diagram()
+}
+
+// Layout returns the bit layout of the IDs generated by the generator
+func (g *Generator) Layout() Layout {
+	return g.layout
+}
@@ -0,0 +1,159 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// commonLayouts are the options of layouts of well known snowflake implementations
+// Sonyflake uses 10ms time units, which is not supported, so only its 16 machine ID bits are copied
+var commonLayouts = []struct {
+	name string
+	opts []Option
+}{
+	{name: "default", opts: nil},
+	{name: "twitter", opts: []Option{WithEpochMillis(1288834974657), WithVersionBit(0)}},
+	{name: "discord", opts: []Option{WithEpochMillis(1420070400000)}},
+	{name: "sonyflake", opts: []Option{WithEpochMillis(1409529600000), WithMachineIDBits(16)}},
+}
+
+// BenchmarkLayouts benchmarks the throughput of BlockingNextID for common layouts
+// Layouts with fewer sequence bits block sooner, which limits their throughput
+func BenchmarkLayouts(b *testing.B) {
+	for _, layout := range commonLayouts {
+		b.Run(layout.name, func(b *testing.B) {
+			generator, err := NewGenerator(1, layout.opts...)
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+			b.ResetTimer()
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				if _, err = generator.BlockingNextID(context.Background()); err != nil {
+					b.Fatalf("expected no error, got %v", err)
+				}
+			}
+			b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "ids/s")
+		})
+	}
+}
+
+// Example_layouts is an example comparing the capacity and lifespan of common layouts
+func Example_layouts() {
+	fmt.Printf("%-10s %12s %8s %s\n", "layout", "machine IDs", "IDs/ms", "exhausted at")
+	for _, layout := range commonLayouts {
+		generator, err := NewGenerator(1, layout.opts...)
+		if err != nil {
+			panic(err)
+		}
+		l := generator.Layout()
+		fmt.Printf("%-10s %12d %8d %s\n", layout.name, l.machineIDMask()+1, l.sequenceMask()+1,
+			generator.EpochExhaustionTime().UTC().Format(time.RFC3339))
+	}
+	// Output:
+	// layout      machine IDs   IDs/ms exhausted at
+	// default            1024     4096 2163-07-14T06:35:11Z
+	// twitter            1024     4096 2080-07-10T17:30:30Z
+	// discord            1024     4096 2154-05-15T07:35:11Z
+	// sonyflake         65536       64 2154-01-13T07:35:11Z
+}
+
+// update rewrites the golden files in testdata instead of comparing against them
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestGenerator_LayoutDiagram tests the LayoutDiagram method of the Generator against golden files
+func TestGenerator_LayoutDiagram(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "default"},
+		{name: "twitter", opts: []Option{WithVersionBit(0)}},
+		{name: "shard_nonce", opts: []Option{WithMachineIDBits(8), WithShardBits(4), WithInstanceNonceBits(2)}},
+		{name: "spread", opts: []Option{WithSpreadLayout(), WithShardBits(2)}},
+		{name: "timestamp_sequence_machine", opts: []Option{WithFieldOrder(OrderTimestampSequenceMachine)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(1, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			got := generator.LayoutDiagram()
+			golden := filepath.Join("testdata", "layout_"+tt.name+".golden")
+			if *update {
+				if err = os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got != string(want) {
+				t.Errorf("expected\n%v\ngot\n%v", string(want), got)
+			}
+		})
+	}
+}
+
+// TestWithFieldOrder tests that generated IDs round-trip through DecodeID and ComposeID with both orders of the
+// sequence and machine ID and that the fields are at their positions
+func TestWithFieldOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order FieldOrder
+		want  ID
+	}{
+		{name: "timestamp machine sequence", order: OrderTimestampMachineSequence, want: 367597485448<<22 | 378<<12 | 5},
+		{name: "timestamp sequence machine", order: OrderTimestampSequenceMachine, want: 367597485448<<22 | 5<<10 | 378},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithInitialSequence(5),
+				WithFieldOrder(tt.order))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			generator.SetTimeFunc(func() uint64 {
+				return 367597485448
+			})
+			id, err := generator.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if id != tt.want {
+				t.Errorf("expected %b, got %b", uint64(tt.want), uint64(id))
+				return
+			}
+			decoded := generator.DecodeID(id)
+			if decoded.Timestamp != 367597485448 || decoded.MachineID != 378 || decoded.Sequence != 5 {
+				t.Errorf("expected timestamp 367597485448, machine ID 378 and sequence 5, got %v", decoded)
+				return
+			}
+			composed, err := ComposeID(decoded, generator.Layout())
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if composed != id {
+				t.Errorf("expected %v, got %v", uint64(id), uint64(composed))
+			}
+		})
+	}
+
+	if _, err := NewGenerator(378, WithFieldOrder(FieldOrder(3))); !errors.Is(err, ErrInvalidFieldOrder) {
+		t.Errorf("expected ErrInvalidFieldOrder, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package snowflake
+
+// WithLogicalClock replaces the clock by a logical clock for environments where the time cannot be trusted at all
+// The timestamp of the IDs starts at the epoch and only advances by one when the sequence is exhausted, it is a
+// counter of exhausted sequences rather than a time, so the time that DecodeID and LastTimestamp return is
+// meaningless. IDs still increase strictly and stay unique within the process, the generator never blocks and the
+// time function, drift and clock related options have no effect, except that strict mode still returns
+// ErrOutOfSequence
+// WARNING: The counter starts at the epoch again after a restart, which reissues the IDs of the previous process. Use
+// a machine ID per process or WithInstanceNonceBits to keep IDs unique across restarts
+func WithLogicalClock() Option {
+	return func(generator *Generator) {
+		generator.logical = true
+	}
+}
@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWithLogicalClock tests that the logical clock advances the timestamp per exhausted sequence, whatever the time
+func TestWithLogicalClock(t *testing.T) {
+	// With 21 machine ID bits there are two sequence numbers per millisecond
+	generator, err := NewGenerator(5, WithMachineIDBits(21), WithEpoch(time.UnixMilli(1288834974657)), WithLogicalClock())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// A broken clock before the epoch that moves back and forth does not matter
+	clock := []uint64{0, 2000000000000, 5, 0, 1, 1}
+	generator.SetTimeFunc(func() uint64 {
+		now := clock[0]
+		clock = append(clock[1:], now)
+		return now
+	})
+
+	// The zero state is the first sequence number of the epoch, so the logical clock starts at sequence 1
+	want := []struct {
+		timestamp uint64
+		sequence  uint64
+	}{{0, 1}, {1, 0}, {1, 1}, {2, 0}, {2, 1}, {3, 0}}
+	var previous ID
+	for _, w := range want {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, w.timestamp, w.sequence)
+		if id <= previous {
+			t.Errorf("expected %v to be greater than %v", id, previous)
+		}
+		previous = id
+		if err = generator.Validate(id); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotEnoughMachineIDs is returned when more machine IDs are requested than fit in the machine ID bits
+	ErrNotEnoughMachineIDs = errors.New("not enough machine IDs")
+)
+
+// AssignMachineIDs returns n distinct machine IDs with the given number of bits for static assignment to a cluster
+// The machine IDs are contiguous, starting at 0, which leaves the highest machine IDs free for growth
+// Generators that each use one of the machine IDs generate unique IDs without coordination
+// Returns an error if the bits are invalid or n machine IDs do not fit in the bits
+func AssignMachineIDs(n int, bits uint64) ([]uint64, error) {
+	if err := checkMachineIDs(n, bits); err != nil {
+		return nil, err
+	}
+	ids := make([]uint64, n)
+	for i := range ids {
+		ids[i] = uint64(i)
+	}
+	return ids, nil
+}
+
+// AssignMachineIDsEven returns n distinct machine IDs with the given number of bits for static assignment to a cluster
+// The machine IDs are evenly distributed over the machine ID space, which leaves room to add machine IDs between them
+// Returns an error if the bits are invalid or n machine IDs do not fit in the bits
+func AssignMachineIDsEven(n int, bits uint64) ([]uint64, error) {
+	if err := checkMachineIDs(n, bits); err != nil {
+		return nil, err
+	}
+	ids := make([]uint64, n)
+	for i := range ids {
+		ids[i] = uint64(i) * (1 << bits) / uint64(n)
+	}
+	return ids, nil
+}
+
+// checkMachineIDs returns an error if the bits are invalid or n machine IDs do not fit in the bits
+func checkMachineIDs(n int, bits uint64) error {
+	if err := (Layout{MachineIDBits: bits}).validate(); err != nil {
+		return err
+	}
+	if n < 0 || uint64(n) > 1<<bits {
+		return fmt.Errorf("%w: %d machine IDs do not fit in %d bits", ErrNotEnoughMachineIDs, n, bits)
+	}
+	return nil
+}
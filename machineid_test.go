@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestAssignMachineIDs tests the AssignMachineIDs and AssignMachineIDsEven functions
+func TestAssignMachineIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		assign func(int, uint64) ([]uint64, error)
+		n      int
+		bits   uint64
+		want   []uint64
+	}{
+		{name: "contiguous", assign: AssignMachineIDs, n: 4, bits: 10, want: []uint64{0, 1, 2, 3}},
+		{name: "contiguous full", assign: AssignMachineIDs, n: 4, bits: 2, want: []uint64{0, 1, 2, 3}},
+		{name: "even", assign: AssignMachineIDsEven, n: 4, bits: 10, want: []uint64{0, 256, 512, 768}},
+		{name: "even uneven", assign: AssignMachineIDsEven, n: 3, bits: 3, want: []uint64{0, 2, 5}},
+		{name: "even full", assign: AssignMachineIDsEven, n: 4, bits: 2, want: []uint64{0, 1, 2, 3}},
+		{name: "none", assign: AssignMachineIDsEven, n: 0, bits: 2, want: []uint64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.assign(tt.n, tt.bits)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestAssignMachineIDs_Errors tests the errors of the AssignMachineIDs and AssignMachineIDsEven functions
+func TestAssignMachineIDs_Errors(t *testing.T) {
+	for _, assign := range []func(int, uint64) ([]uint64, error){AssignMachineIDs, AssignMachineIDsEven} {
+		if _, err := assign(5, 2); !errors.Is(err, ErrNotEnoughMachineIDs) {
+			t.Errorf("expected ErrNotEnoughMachineIDs, got %v", err)
+		}
+		if _, err := assign(-1, 2); !errors.Is(err, ErrNotEnoughMachineIDs) {
+			t.Errorf("expected ErrNotEnoughMachineIDs, got %v", err)
+		}
+		if _, err := assign(1, 0); !errors.Is(err, ErrMachineBitsTooSmall) {
+			t.Errorf("expected ErrMachineBitsTooSmall, got %v", err)
+		}
+		if _, err := assign(1, 22); !errors.Is(err, ErrMachineBitsTooLarge) {
+			t.Errorf("expected ErrMachineBitsTooLarge, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	// ErrNullID is returned when a StrictID is unmarshaled from JSON null
+	ErrNullID = errors.New("ID is null")
+)
+
+// MarshalJSON marshals the snowflake ID as a quoted decimal string, because JSON numbers above 2^53 lose precision
+// in JavaScript
+func (id ID) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 22)
+	b = append(b, '"')
+	b = strconv.AppendUint(b, uint64(id), 10)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON unmarshals the snowflake ID from a quoted decimal string or a JSON number
+// JSON null unmarshals to the zero ID, so an ID field does not have to be a pointer to accept null, use StrictID to
+// reject null instead
+// Returns an error if the value is not a valid ID
+func (id *ID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*id = 0
+		return nil
+	}
+	parsed, err := unmarshalJSONID(b)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// unmarshalJSONID parses a snowflake ID from a quoted decimal string or a JSON number
+func unmarshalJSONID(b []byte) (ID, error) {
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		b = b[1 : len(b)-1]
+	}
+	return ParseIDFast(b)
+}
+
+// StrictID is a snowflake ID that rejects JSON null, for fields where a missing ID is an error
+// It marshals like ID, convert it with ID(strictID)
+type StrictID ID
+
+// MarshalJSON marshals the snowflake ID as a quoted decimal string like ID
+func (id StrictID) MarshalJSON() ([]byte, error) {
+	return ID(id).MarshalJSON()
+}
+
+// UnmarshalJSON unmarshals the snowflake ID from a quoted decimal string or a JSON number like ID
+// Returns ErrNullID for JSON null and an error if the value is not a valid ID
+func (id *StrictID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return ErrNullID
+	}
+	parsed, err := unmarshalJSONID(b)
+	if err != nil {
+		return err
+	}
+	*id = StrictID(parsed)
+	return nil
+}
+
+// MarshalYAML marshals the snowflake ID as a decimal string
+// It implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3
+func (id ID) MarshalYAML() (interface{}, error) {
+	return strconv.FormatUint(uint64(id), 10), nil
+}
+
+// UnmarshalYAML unmarshals the snowflake ID from a decimal string or an integer
+// It implements the Unmarshaler interface of gopkg.in/yaml.v2, which is also supported by gopkg.in/yaml.v3, so
+// this module does not depend on a YAML library
+// Returns an error if the value is not a scalar or not a valid ID
+func (id *ID) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case string:
+		parsed, err := ParseID(v)
+		if err != nil {
+			return err
+		}
+		*id = parsed
+	case int:
+		if v < 0 {
+			return fmt.Errorf("%w: %d", ErrInvalidID, v)
+		}
+		*id = ID(v)
+	case uint64:
+		*id = ID(v)
+	default:
+		return fmt.Errorf("%w: unsupported YAML value %v of type %T", ErrInvalidID, v, v)
+	}
+	return nil
+}
+
+// MarshalText marshals the snowflake ID as a decimal string
+// It implements encoding.TextMarshaler, which is used for map keys in JSON and by text based encoders
+func (id ID) MarshalText() ([]byte, error) {
+	return strconv.AppendUint(make([]byte, 0, 20), uint64(id), 10), nil
+}
+
+// UnmarshalText unmarshals the snowflake ID from a decimal string
+// It implements encoding.TextUnmarshaler
+// Returns an error wrapping ErrInvalidID if the text is empty, not decimal or out of range, the ID is unchanged then
+func (id *ID) UnmarshalText(b []byte) error {
+	parsed, err := ParseIDFast(b)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
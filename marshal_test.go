@@ -0,0 +1,241 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestID_MarshalYAML tests that MarshalYAML marshals an ID as a decimal string
+func TestID_MarshalYAML(t *testing.T) {
+	got, err := ID(math.MaxUint64).MarshalYAML()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got != "18446744073709551615" {
+		t.Errorf("expected 18446744073709551615, got %v", got)
+	}
+}
+
+// TestID_UnmarshalYAML tests that UnmarshalYAML unmarshals an ID from the values a YAML library decodes scalars to
+func TestID_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    ID
+		wantErr error
+	}{
+		{name: "string", value: "1541815603606036480", want: 1541815603606036480},
+		{name: "int", value: 1541815603606036480, want: 1541815603606036480},
+		{name: "uint64", value: uint64(math.MaxUint64), want: math.MaxUint64},
+		{name: "zero", value: 0, want: 0},
+		{name: "negative int", value: -1, wantErr: ErrInvalidID},
+		{name: "invalid string", value: "abc", wantErr: ErrInvalidID},
+		{name: "mapping", value: map[string]interface{}{"id": 1}, wantErr: ErrInvalidID},
+		{name: "sequence", value: []interface{}{1}, wantErr: ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id ID
+			err := id.UnmarshalYAML(func(v interface{}) error {
+				*v.(*interface{}) = tt.value
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if id != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(id))
+			}
+		})
+	}
+}
+
+// TestID_MarshalJSON tests that MarshalJSON marshals an ID as a quoted decimal string
+func TestID_MarshalJSON(t *testing.T) {
+	got, err := json.Marshal(struct {
+		ID ID `json:"id"`
+	}{ID: 1541815603606036480})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if want := `{"id":"1541815603606036480"}`; string(got) != want {
+		t.Errorf("expected %v, got %v", want, string(got))
+	}
+}
+
+// TestID_UnmarshalJSON tests that UnmarshalJSON accepts strings, numbers and null, and rejects malformed values
+func TestID_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    ID
+		wantErr error
+	}{
+		{name: "string", json: `{"id":"1541815603606036480"}`, want: 1541815603606036480},
+		{name: "number", json: `{"id":1541815603606036480}`, want: 1541815603606036480},
+		{name: "null", json: `{"id":null}`, want: 0},
+		{name: "empty string", json: `{"id":""}`, wantErr: ErrInvalidID},
+		{name: "invalid string", json: `{"id":"abc"}`, wantErr: ErrInvalidID},
+		{name: "negative", json: `{"id":-1}`, wantErr: ErrInvalidID},
+		{name: "fraction", json: `{"id":1.5}`, wantErr: ErrInvalidID},
+		{name: "too large", json: `{"id":"18446744073709551616"}`, wantErr: ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A non-zero ID makes sure null resets the ID
+			v := struct {
+				ID ID `json:"id"`
+			}{ID: 1}
+			err := json.Unmarshal([]byte(tt.json), &v)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if err == nil && v.ID != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(v.ID))
+			}
+		})
+	}
+}
+
+// TestID_JSONRoundTrip tests that IDs survive a JSON round trip as strings and decode from stored numbers
+func TestID_JSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		id   ID
+		json string
+	}{
+		{name: "zero", id: 0, json: `"0"`},
+		{name: "twitter", id: 1541815603606036480, json: `"1541815603606036480"`},
+		{name: "max", id: math.MaxUint64, json: `"18446744073709551615"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.id)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if string(b) != tt.json {
+				t.Errorf("expected %v, got %v", tt.json, string(b))
+				return
+			}
+			var fromString, fromNumber ID = 1, 1
+			if err := json.Unmarshal(b, &fromString); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			// Documents that stored the ID as a number still decode
+			if err := json.Unmarshal(b[1:len(b)-1], &fromNumber); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if fromString != tt.id || fromNumber != tt.id {
+				t.Errorf("expected %v, got %v and %v", uint64(tt.id), uint64(fromString), uint64(fromNumber))
+			}
+		})
+	}
+}
+
+// TestStrictID_UnmarshalJSON tests that StrictID rejects null and otherwise behaves like ID
+func TestStrictID_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    StrictID
+		wantErr error
+	}{
+		{name: "string", json: `{"id":"1541815603606036480"}`, want: 1541815603606036480},
+		{name: "number", json: `{"id":1541815603606036480}`, want: 1541815603606036480},
+		{name: "null", json: `{"id":null}`, wantErr: ErrNullID},
+		{name: "invalid string", json: `{"id":"abc"}`, wantErr: ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v struct {
+				ID StrictID `json:"id"`
+			}
+			err := json.Unmarshal([]byte(tt.json), &v)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if v.ID != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(v.ID))
+			}
+		})
+	}
+
+	got, err := json.Marshal(StrictID(42))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if string(got) != `"42"` {
+		t.Errorf("expected %v, got %v", `"42"`, string(got))
+	}
+}
+
+// TestID_MarshalText tests that MarshalText marshals an ID as a decimal string, also as a JSON map key
+func TestID_MarshalText(t *testing.T) {
+	got, err := ID(1541815603606036480).MarshalText()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if want := "1541815603606036480"; string(got) != want {
+		t.Errorf("expected %v, got %v", want, string(got))
+	}
+	got, err = json.Marshal(map[ID]int{1541815603606036480: 1})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if want := `{"1541815603606036480":1}`; string(got) != want {
+		t.Errorf("expected %v, got %v", want, string(got))
+	}
+}
+
+// TestID_UnmarshalText tests that UnmarshalText parses decimal strings and rejects other input without changing the ID
+func TestID_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    ID
+		wantErr error
+	}{
+		{name: "decimal", text: "1541815603606036480", want: 1541815603606036480},
+		{name: "zero", text: "0", want: 0},
+		{name: "max", text: "18446744073709551615", want: math.MaxUint64},
+		{name: "empty", text: "", want: 1, wantErr: ErrInvalidID},
+		{name: "not decimal", text: "abc", want: 1, wantErr: ErrInvalidID},
+		{name: "negative", text: "-1", want: 1, wantErr: ErrInvalidID},
+		{name: "out of range", text: "18446744073709551616", want: 1, wantErr: ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := ID(1)
+			if err := id.UnmarshalText([]byte(tt.text)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if id != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(id))
+			}
+		})
+	}
+
+	var m map[ID]int
+	if err := json.Unmarshal([]byte(`{"1541815603606036480":1}`), &m); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if m[1541815603606036480] != 1 {
+		t.Errorf("expected 1, got %v", m)
+	}
+}
@@ -0,0 +1,47 @@
+package snowflake
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTimestampOverflow is returned when a timestamp does not fit in the timestamp bits of the layout
+	ErrTimestampOverflow = errors.New("timestamp overflow")
+)
+
+// MigrateID re-bases the timestamp of an ID from one epoch to another
+// The machine ID, shard and sequence bits are preserved
+// Returns an error if the re-based timestamp is before the new epoch
+// Returns an error if the re-based timestamp does not fit in the timestamp bits of the layout
+func MigrateID(old ID, fromEpoch, toEpoch time.Time, layout Layout) (ID, error) {
+	if err := layout.validate(); err != nil {
+		return 0, err
+	}
+	shift := layout.timestampShift()
+	timestamp := int64(uint64(old)>>shift&layout.timestampMask()) + fromEpoch.UnixMilli() - toEpoch.UnixMilli()
+	if timestamp < 0 {
+		return 0, ErrTimeBeforeEpoch
+	}
+	if uint64(timestamp) > layout.timestampMask() {
+		return 0, ErrTimestampOverflow
+	}
+	return ID(uint64(timestamp)<<shift | uint64(old)&^(layout.timestampMask()<<shift)), nil
+}
+
+// RelayoutDecode decodes an ID with the layout it was generated with and checks that its components fit in another
+// layout, for example to analyze a change of the number of machine ID bits before migrating
+// The stored ID does not change, only its interpretation: the returned components are those of the from layout, and
+// ComposeID with the to layout gives the ID that they have in the new layout
+// Returns the error of ComposeID wrapped with the offending value if a component does not fit in the to layout, the
+// components are returned as well so the other fields can still be inspected
+func RelayoutDecode(id ID, from, to Layout) (DecodedID, error) {
+	if err := from.validate(); err != nil {
+		return DecodedID{}, err
+	}
+	decoded := DecodeID(id, from)
+	if _, err := ComposeID(decoded, to); err != nil {
+		return decoded, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,138 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestMigrateID tests the MigrateID function
+func TestMigrateID(t *testing.T) {
+	twitterEpoch := time.UnixMilli(1288834974657)
+	tests := []struct {
+		name      string
+		id        ID
+		fromEpoch time.Time
+		toEpoch   time.Time
+		layout    Layout
+		want      ID
+		wantErr   error
+	}{
+		{
+			name:      "Test MigrateID from the Twitter epoch to the Unix epoch",
+			id:        1541815603606036480,
+			fromEpoch: twitterEpoch,
+			toEpoch:   time.UnixMilli(0),
+			layout:    DefaultLayout(),
+			want:      ID(1656432460105<<timeShift | 378<<12),
+		},
+		{
+			name:      "Test MigrateID from the Unix epoch to the Twitter epoch",
+			id:        ID(1656432460105<<timeShift | 378<<12 | 7),
+			fromEpoch: time.UnixMilli(0),
+			toEpoch:   twitterEpoch,
+			layout:    DefaultLayout(),
+			want:      1541815603606036487,
+		},
+		{
+			name:      "Test MigrateID to an epoch after the ID",
+			id:        1541815603606036480,
+			fromEpoch: twitterEpoch,
+			toEpoch:   time.UnixMilli(1656432460106),
+			layout:    DefaultLayout(),
+			wantErr:   ErrTimeBeforeEpoch,
+		},
+		{
+			name:      "Test MigrateID overflowing the timestamp bits",
+			id:        ID(1<<64 - 1<<timeShift | 378<<12),
+			fromEpoch: twitterEpoch,
+			toEpoch:   time.UnixMilli(0),
+			layout:    DefaultLayout(),
+			wantErr:   ErrTimestampOverflow,
+		},
+		{
+			name:      "Test MigrateID with the spread layout",
+			id:        ID(7<<52 | 367597485448<<10 | 378),
+			fromEpoch: twitterEpoch,
+			toEpoch:   time.UnixMilli(0),
+			layout:    Layout{MachineIDBits: 10, Order: OrderSequenceTimestampMachine},
+			want:      ID(7<<52 | 1656432460105<<10 | 378),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MigrateID(tt.id, tt.fromEpoch, tt.toEpoch, tt.layout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(got))
+			}
+		})
+	}
+}
+
+// TestRelayoutDecode tests that RelayoutDecode checks the components of the old layout against the new layout
+func TestRelayoutDecode(t *testing.T) {
+	twitter := DecodedID{ID: 1541815603606036480, Timestamp: 367597485448, MachineID: 378}
+	tests := []struct {
+		name    string
+		id      ID
+		from    Layout
+		to      Layout
+		want    DecodedID
+		wantErr error
+	}{
+		{
+			name: "more machine ID bits",
+			id:   1541815603606036480,
+			from: DefaultLayout(),
+			to:   Layout{MachineIDBits: 16},
+			want: twitter,
+		},
+		{
+			name:    "machine ID does not fit",
+			id:      1541815603606036480,
+			from:    DefaultLayout(),
+			to:      Layout{MachineIDBits: 8},
+			want:    twitter,
+			wantErr: ErrMachineIDTooLarge,
+		},
+		{
+			name:    "sequence does not fit",
+			id:      367597485448<<22 | 378<<12 | 4095,
+			from:    DefaultLayout(),
+			to:      Layout{MachineIDBits: 16},
+			want:    DecodedID{ID: 367597485448<<22 | 378<<12 | 4095, Timestamp: 367597485448, MachineID: 378, Sequence: 4095},
+			wantErr: ErrSequenceTooLarge,
+		},
+		{
+			name:    "version does not fit",
+			id:      1<<63 | 1541815603606036480,
+			from:    Layout{VersionBit: true, MachineIDBits: 10},
+			to:      DefaultLayout(),
+			want:    DecodedID{ID: 1<<63 | 1541815603606036480, Version: 1, Timestamp: 367597485448, MachineID: 378},
+			wantErr: ErrVersionTooLarge,
+		},
+		{
+			name:    "invalid from layout",
+			id:      1541815603606036480,
+			from:    Layout{},
+			to:      DefaultLayout(),
+			wantErr: ErrMachineBitsTooSmall,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RelayoutDecode(tt.id, tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotMonotonic is returned when WithMonotonic is combined with an option that generates IDs out of order
+	ErrNotMonotonic = errors.New("generator is not monotonic")
+)
+
+// WithMonotonicityAssertion makes the generator panic when the state it reserves is not strictly greater than the
+// previous state, which catches regressions in the concurrency and clock handling of the generator immediately
+// The state holds the timestamp and sequence, so with the default field order every ID is strictly greater than the
+// previous ID. The check is one comparison per reservation, but it panics instead of returning an error, so it is
+// meant for tests and staging and is disabled by default
+func WithMonotonicityAssertion() Option {
+	return func(generator *Generator) {
+		generator.assertMonotonic = true
+	}
+}
+
+// assertMonotonic panics if the reserved state first is not strictly greater than the previous state
+func assertMonotonic(previous uint64, first uint64) {
+	if first <= previous {
+		panic(fmt.Sprintf("snowflake: reserved state %d is not greater than the previous state %d", first, previous))
+	}
+}
+
+// WithMonotonic guarantees that every ID of NextID, BlockingNextID, NextIDs and the other methods that continue the
+// sequence of the generator is strictly greater than every ID generated before it, including the IDs of concurrent
+// callers and the IDs generated while the clock moves backwards or the generator is throttled
+// The timestamp and sequence are reserved with a single compare-and-swap on a state that never decreases, so the
+// guarantee holds when the machine ID and the field order do not change the order of the IDs
+// NewGenerator returns ErrNotMonotonic when it is combined with OrderSequenceTimestampMachine, shard bits, a sequence
+// strategy or machine ID collision recovery, and NextIDAt returns ErrNotMonotonic because it generates IDs
+// before the last generated ID
+func WithMonotonic() Option {
+	return func(generator *Generator) {
+		generator.monotonic = true
+	}
+}
+
+// validateMonotonic returns ErrNotMonotonic if the generator is monotonic and an option generates IDs out of order
+func (g *Generator) validateMonotonic() error {
+	if !g.monotonic {
+		return nil
+	}
+	switch {
+	case g.layout.Order == OrderSequenceTimestampMachine:
+		return fmt.Errorf("%w: the sequence is not in the least significant bits", ErrNotMonotonic)
+	case g.layout.ShardBits > 0:
+		return fmt.Errorf("%w: the shard is above the sequence", ErrNotMonotonic)
+	case g.strategy != nil:
+		return fmt.Errorf("%w: the sequence strategy chooses the sequence", ErrNotMonotonic)
+	case g.recovery != nil:
+		return fmt.Errorf("%w: collision recovery changes the machine ID", ErrNotMonotonic)
+	}
+	return nil
+}
+
+// LastID returns the highest ID generated by the methods that continue the sequence of the generator, or 0 if no ID
+// has been generated yet
+// The shard is zero, so with shard bits the ID can be lower than an ID of NextIDForShard
+// With WithMonotonic every ID generated after LastID returns is greater than the ID it returns
+func (g *Generator) LastID() ID {
+	state := g.currentID.Load()
+	if state == 0 {
+		return 0
+	}
+	return g.compose(state, g.machineID.Load(), 0)
+}
@@ -0,0 +1,199 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWithMonotonicityAssertion tests that concurrent generation on a clock that jumps back and forth does not trigger
+// the assertion
+func TestWithMonotonicityAssertion(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second),
+		WithMonotonicityAssertion())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var calls atomic.Uint64
+	generator.SetTimeFunc(func() uint64 {
+		// Every eighth reading is 5ms behind
+		if calls.Add(1)%8 == 0 {
+			return 367597485448 - 5
+		}
+		return 367597485448 + calls.Load()/1000
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				if _, err := generator.NextID(); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestAssertMonotonic tests that assertMonotonic panics on a state that is not strictly greater
+func TestAssertMonotonic(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  uint64
+		first     uint64
+		wantPanic bool
+	}{
+		{name: "greater", previous: 1, first: 2, wantPanic: false},
+		{name: "equal", previous: 2, first: 2, wantPanic: true},
+		{name: "smaller", previous: 3, first: 2, wantPanic: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("expected panic %v, got %v", tt.wantPanic, r)
+				}
+			}()
+			assertMonotonic(tt.previous, tt.first)
+		})
+	}
+}
+
+// TestWithMonotonic tests that WithMonotonic rejects the options that generate IDs out of order
+func TestWithMonotonic(t *testing.T) {
+	recovery := func(ctx context.Context) (uint64, error) {
+		return 2, nil
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want error
+	}{
+		{name: "default layout", opts: []Option{}},
+		{name: "nonce and version", opts: []Option{WithInstanceNonceBits(2), WithVersionBit(1)}},
+		{name: "spread layout", opts: []Option{WithSpreadLayout()}, want: ErrNotMonotonic},
+		{name: "shard bits", opts: []Option{WithShardBits(2)}, want: ErrNotMonotonic},
+		{name: "sequence strategy", opts: []Option{WithSequenceStrategy(NewIncrementSequenceStrategy(12))},
+			want: ErrNotMonotonic},
+		{name: "collision recovery", opts: []Option{WithMachineIDCollisionRecovery(recovery)}, want: ErrNotMonotonic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenerator(1, append(tt.opts, WithMonotonic())...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+				return
+			}
+			// The options are valid without WithMonotonic
+			if _, err = NewGenerator(1, tt.opts...); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+
+	generator, err := NewGenerator(1, WithMonotonic())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextIDAt(time.Now()); !errors.Is(err, ErrNotMonotonic) {
+		t.Errorf("expected ErrNotMonotonic, got %v", err)
+	}
+}
+
+// TestWithMonotonic_Concurrent tests that concurrent callers on a clock that jumps back and forth get strictly
+// increasing IDs that are greater than the LastID before the call
+func TestWithMonotonic_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second), WithMonotonic())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var calls atomic.Uint64
+	generator.SetTimeFunc(func() uint64 {
+		// Every eighth reading is 5ms behind
+		if calls.Add(1)%8 == 0 {
+			return 367597485448 - 5
+		}
+		return 367597485448 + calls.Load()/1000
+	})
+
+	const goroutines = 8
+	const count = 10000
+	results := make(chan []ID, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]ID, 0, count)
+			for len(ids) < count {
+				last := generator.LastID()
+				id, err := generator.NextID()
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					break
+				}
+				if id <= last {
+					t.Errorf("expected %v to be greater than the last ID %v", uint64(id), uint64(last))
+					break
+				}
+				if len(ids) > 0 && id <= ids[len(ids)-1] {
+					t.Errorf("expected %v to be greater than %v", uint64(id), uint64(ids[len(ids)-1]))
+					break
+				}
+				ids = append(ids, id)
+			}
+			results <- ids
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var all []ID
+	for ids := range results {
+		all = append(all, ids...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i] < all[j]
+	})
+	for i := 1; i < len(all); i++ {
+		if all[i] <= all[i-1] {
+			t.Errorf("expected %v to be greater than %v", uint64(all[i]), uint64(all[i-1]))
+			return
+		}
+	}
+	if last := generator.LastID(); last != all[len(all)-1] {
+		t.Errorf("expected %v, got %v", uint64(all[len(all)-1]), uint64(last))
+	}
+}
+
+// TestGenerator_LastID tests that LastID returns 0 before the first ID and then the highest generated ID
+func TestGenerator_LastID(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if last := generator.LastID(); last != 0 {
+		t.Errorf("expected 0, got %v", uint64(last))
+		return
+	}
+	ids, err := generator.NextIDs(10)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if last := generator.LastID(); last != ids[len(ids)-1] {
+		t.Errorf("expected %v, got %v", uint64(ids[len(ids)-1]), uint64(last))
+	}
+}
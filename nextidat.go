@@ -0,0 +1,136 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrTimeAlreadyUsed is returned by NextIDAt when the time is between the first and the last generated ID, where
+	// the sequence that was used is not known
+	ErrTimeAlreadyUsed = errors.New("time is already used by the sequence of the generator")
+)
+
+// WithMaxFutureOffset sets how far in the future the time passed to NextIDAt may be, the default is zero
+func WithMaxFutureOffset(d time.Duration) Option {
+	return func(generator *Generator) {
+		generator.maxFutureOffset = d
+	}
+}
+
+// NextIDAt generates a new snowflake ID with the timestamp of t, for example for an event that is scheduled later or
+// to backfill historical records with IDs that hold the time of the original event
+// t may be in the past, or in the future up to the offset set with WithMaxFutureOffset
+// A time at or after the last generated ID continues the sequence of NextID, so the IDs of NextIDAt and NextID never
+// collide. A time in the future moves the last generated ID forward: NextID continues after it like after drift,
+// and in strict mode returns ErrClockMovedBackwards until the clock reaches it
+// A time before the first generated ID, for example to backfill historical records after generating live IDs, has
+// a sequence per millisecond, which is kept until Reset. The generator does not know the sequence of the
+// milliseconds between its first and last ID, so backfill records sorted by time, newest first, after generating
+// live IDs, or oldest first before
+// Returns ErrTimeBeforeEpoch when t is before the epoch and ErrTimestampOverflow when it does not fit in the timestamp
+// bits
+// Returns ErrTimeInFuture wrapped with the offending values when t is beyond the maximum future offset
+// Returns ErrTimeAlreadyUsed wrapped with the offending values when t is at or after the first and before the last
+// generated ID
+// Returns ErrOutOfSequence when the sequence of the millisecond of t is exhausted
+// Returns ErrNotMonotonic when the generator is created with WithMonotonic
+func (g *Generator) NextIDAt(t time.Time) (ID, error) {
+	if g.monotonic {
+		return 0, fmt.Errorf("%w: NextIDAt generates IDs before the last generated ID", ErrNotMonotonic)
+	}
+	at := int64(g.quantize(uint64(g.ticks(t)))) - g.epoch
+	if at < 0 {
+		return 0, ErrTimeBeforeEpoch
+	}
+	if uint64(at) > g.layout.timestampMask() {
+		return 0, ErrTimestampOverflow
+	}
+	limit := int64(g.now()) - g.epoch + int64(g.units(g.maxFutureOffset))
+	if at > limit {
+		return 0, fmt.Errorf("%w: timestamp %d is after %d", ErrTimeInFuture, at, limit)
+	}
+
+	if state, ok, err := g.reserveAt(uint64(at)); ok {
+		if err != nil {
+			return 0, err
+		}
+		return g.issue(state, g.machineID.Load(), 0), nil
+	}
+
+	g.pastMu.Lock()
+	defer g.pastMu.Unlock()
+	// The state is past at, so the floor is set
+	if floor := g.floor.Load() - 1; uint64(at) >= floor {
+		return 0, fmt.Errorf("%w: timestamp %d is not before the first generated ID at %d", ErrTimeAlreadyUsed, at,
+			floor)
+	}
+	sequence, ok := g.past[uint64(at)]
+	if ok {
+		if sequence == g.sequenceMask {
+			g.sequenceExhausted()
+			return 0, ErrOutOfSequence
+		}
+		sequence++
+	}
+	if g.past == nil {
+		g.past = make(map[uint64]uint64)
+	}
+	g.past[uint64(at)] = sequence
+	return g.issue(uint64(at)<<g.stateShift|sequence, g.machineID.Load(), 0), nil
+}
+
+// reserveAt reserves the next sequence number of the state of NextID at the timestamp at and returns its state
+// ok is false when the last generated ID is after at, the state is then left as it is
+// Returns ErrOutOfSequence when the sequence of at is exhausted, the ID must have the timestamp of at so it does not
+// drift into the next millisecond
+func (g *Generator) reserveAt(at uint64) (state uint64, ok bool, err error) {
+	if currentID := g.currentID.Load(); currentID != 0 && currentID>>g.stateShift > at {
+		return 0, false, nil
+	}
+	if g.strategy != nil {
+		_, state, err = g.reserveFromStrategy(at)
+		if errors.Is(err, ErrClockMovedBackwards) || err == nil && state>>g.stateShift != at {
+			// Another call moved the state past at in the meantime
+			return 0, false, nil
+		}
+		return state, true, err
+	}
+	for {
+		currentID := g.currentID.Load()
+		if currentID != 0 && currentID>>g.stateShift > at {
+			return 0, false, nil
+		}
+		first, _, err := g.advance(currentID, at, 1)
+		if err == nil && first>>g.stateShift != at {
+			err = ErrOutOfSequence
+		}
+		if err != nil {
+			if errors.Is(err, ErrOutOfSequence) {
+				g.sequenceExhausted()
+			}
+			return 0, true, err
+		}
+		if currentID == 0 {
+			g.lowerFloor(first)
+		}
+		if g.currentID.CompareAndSwap(currentID, first) {
+			return first, true, nil
+		}
+	}
+}
+
+// lowerFloor lowers the floor to the timestamp of the state, the floor is one more than the lowest timestamp the state
+// of NextID ever had and zero before the first ID
+// It is called before the state leaves zero, so NextIDAt only has a sequence of its own for times before any ID that
+// NextID generated
+func (g *Generator) lowerFloor(state uint64) {
+	floor := state>>g.stateShift + 1
+	for {
+		current := g.floor.Load()
+		if current != 0 && current <= floor || g.floor.CompareAndSwap(current, floor) {
+			return
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestGenerator_NextIDAt tests that NextIDAt generates IDs for the given time and that NextID continues after the IDs
+// it generated in the future
+func TestGenerator_NextIDAt(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithMaxFutureOffset(5*time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	for sequence := uint64(0); sequence < 3; sequence++ {
+		id, err := generator.NextIDAt(time.UnixMilli(367597490448))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, 367597490448, sequence)
+	}
+
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, 367597490448, 3)
+
+	id, err = generator.NextIDAt(time.UnixMilli(367597485000))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, 367597485000, 0)
+}
+
+// TestGenerator_NextIDAt_Future tests that NextID does not reissue the IDs NextIDAt generated in the future once the
+// clock reaches their millisecond
+func TestGenerator_NextIDAt_Future(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "sequence"},
+		{name: "strategy", opts: []Option{WithSequenceStrategy(NewIncrementSequenceStrategy(12))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(378, append([]Option{WithEpoch(time.UnixMilli(0)),
+				WithMaxFutureOffset(time.Second)}, tt.opts...)...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			now := uint64(367597485448)
+			generator.SetTimeFunc(func() uint64 {
+				return now
+			})
+
+			seen := make(map[ID]bool)
+			for i := 0; i < 3; i++ {
+				id, err := generator.NextIDAt(time.UnixMilli(367597485450))
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				seen[id] = true
+			}
+			for ; now < 367597485452; now++ {
+				id, err := generator.NextID()
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				if seen[id] {
+					t.Errorf("expected a unique ID, got %v again", uint64(id))
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
+
+// TestGenerator_NextIDAt_FutureStrict tests that NextID in strict mode reports the IDs NextIDAt generated in the
+// future as a clock that moved backwards
+func TestGenerator_NextIDAt_FutureStrict(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithMaxFutureOffset(time.Second), WithStrict())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	if _, err = generator.NextIDAt(time.UnixMilli(367597485450)); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("expected %v, got %v", ErrClockMovedBackwards, err)
+	}
+	now += 2
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, 367597485450, 1)
+}
+
+// TestGenerator_NextIDAt_Unique tests that NextIDAt and NextID do not generate the same ID when they are mixed in the
+// same millisecond and NextIDAt is called with the same past time repeatedly
+func TestGenerator_NextIDAt_Unique(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	seen := make(map[ID]bool)
+	generate := func(next func() (ID, error)) bool {
+		id, err := next()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return false
+		}
+		if seen[id] {
+			t.Errorf("expected a unique ID, got %v again", uint64(id))
+			return false
+		}
+		seen[id] = true
+		return true
+	}
+	past := time.UnixMilli(367597485000)
+	for i := 0; i < 5; i++ {
+		if !generate(generator.NextID) {
+			return
+		}
+		if !generate(func() (ID, error) { return generator.NextIDAt(time.UnixMilli(int64(now))) }) {
+			return
+		}
+		if !generate(func() (ID, error) { return generator.NextIDAt(past) }) {
+			return
+		}
+	}
+	if len(seen) != 15 {
+		t.Errorf("expected 15 IDs, got %v", len(seen))
+	}
+}
+
+// TestGenerator_NextIDAt_TimeAlreadyUsed tests that NextIDAt returns ErrTimeAlreadyUsed for a time between the first
+// and the last generated ID, and generates IDs for times before the first ID
+func TestGenerator_NextIDAt_TimeAlreadyUsed(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	for _, at := range []int64{367597485000, 367597485100} {
+		if _, err = generator.NextIDAt(time.UnixMilli(at)); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if _, err = generator.NextIDAt(time.UnixMilli(367597485000)); !errors.Is(err, ErrTimeAlreadyUsed) {
+		t.Errorf("expected %v, got %v", ErrTimeAlreadyUsed, err)
+	}
+	id, err := generator.NextIDAt(time.UnixMilli(367597484999))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, 367597484999, 0)
+
+	generator.Reset()
+	id, err = generator.NextIDAt(time.UnixMilli(367597485000))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, 367597485000, 0)
+}
+
+// TestGenerator_NextIDAt_Errors tests the errors of NextIDAt
+func TestGenerator_NextIDAt_Errors(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(1000)), WithMaxFutureOffset(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 5000
+	})
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		wantErr error
+	}{
+		{name: "before epoch", at: time.UnixMilli(999), wantErr: ErrTimeBeforeEpoch},
+		{name: "at maximum future offset", at: time.UnixMilli(6000)},
+		{name: "beyond maximum future offset", at: time.UnixMilli(6001), wantErr: ErrTimeInFuture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generator.NextIDAt(tt.at); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// ExampleGenerator_NextIDAt is an example of backfilling historical records with IDs that hold their event time
+func ExampleGenerator_NextIDAt() {
+	generator, err := NewGenerator(1, WithEpoch(TwitterEpoch))
+	if err != nil {
+		panic(err)
+	}
+	events := []time.Time{
+		time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2020, 7, 15, 8, 30, 0, 0, time.UTC),
+	}
+	for _, event := range events {
+		id, err := generator.NextIDAt(event)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(uint64(id), generator.Time(id).Format(time.RFC3339), generator.DecodeID(id).Sequence)
+	}
+	// Output:
+	// 1123557678904250368 2019-05-01T12:00:00Z 0
+	// 1123557678904250369 2019-05-01T12:00:00Z 1
+	// 1283317879403450368 2020-07-15T08:30:00Z 0
+}
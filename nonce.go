@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var (
+	// ErrNonceBitsTooLarge is returned when the machine ID, shard and nonce bits leave no room for the sequence
+	ErrNonceBitsTooLarge = errors.New("nonce bits is too large")
+)
+
+// WithInstanceNonceBits reserves the given number of bits for a random nonce that is chosen once in NewGenerator
+// The nonce is placed between the shard and the sequence, and is taken from the sequence
+// When a machine ID is reused by a new process, for example after a crash, the new process can generate IDs in the
+// same milliseconds as the old process did. These IDs only collide when both processes picked the same nonce, which
+// happens with a probability of 1/2^n: 1/16 for 4 bits, 1/256 for 8 bits and 1/4096 for 12 bits
+// Every nonce bit halves the number of IDs per millisecond, this is a lighter alternative to persisting state
+func WithInstanceNonceBits(size uint64) Option {
+	return func(generator *Generator) {
+		generator.layout.NonceBits = size
+	}
+}
+
+// CollisionProbability returns the probability that at least two of the given number of instances that share a
+// machine ID pick the same nonce with the given number of nonce bits, using the birthday bound 1 - e^(-k(k-1)/2^(n+1))
+// The approximation is close when the number of instances is small compared to the number of nonces, for example 2
+// instances with 8 bits collide with a probability of about 1/256 and 77163 instances with 32 bits with about 1/2
+// Returns 0 for less than two instances and 1 when there are more instances than nonces
+func CollisionProbability(instances int, nonceBits uint64) float64 {
+	if instances < 2 {
+		return 0
+	}
+	nonces := math.Ldexp(1, int(nonceBits))
+	k := float64(instances)
+	if k > nonces {
+		return 1
+	}
+	return -math.Expm1(-k * (k - 1) / (2 * nonces))
+}
+
+// randomNonce returns a random nonce within the mask
+func randomNonce(mask uint64) (uint64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]) & mask, nil
+}
@@ -0,0 +1,94 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestWithInstanceNonceBits tests that every ID contains the nonce of the generator
+func TestWithInstanceNonceBits(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithShardBits(2), WithInstanceNonceBits(4))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	if generator.sequenceMask != 1<<6-1 {
+		t.Errorf("expected sequence mask %v, got %v", 1<<6-1, generator.sequenceMask)
+	}
+	for sequence := uint64(0); sequence < 3; sequence++ {
+		id, err := generator.NextIDForShard(3)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		want := DecodedID{
+			ID:        uint64(id),
+			Timestamp: 367597485448,
+			MachineID: 378,
+			Shard:     3,
+			Nonce:     generator.nonce,
+			Sequence:  sequence,
+		}
+		if got := generator.DecodeID(id); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
+
+// TestWithInstanceNonceBits_Random tests that generators pick different nonces
+func TestWithInstanceNonceBits_Random(t *testing.T) {
+	nonces := make(map[uint64]bool)
+	for i := 0; i < 100; i++ {
+		generator, err := NewGenerator(378, WithInstanceNonceBits(8))
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if generator.nonce > 1<<8-1 {
+			t.Errorf("expected nonce to fit in 8 bits, got %v", generator.nonce)
+		}
+		nonces[generator.nonce] = true
+	}
+	if len(nonces) < 2 {
+		t.Errorf("expected different nonces, got %v", nonces)
+	}
+}
+
+// TestWithInstanceNonceBits_Errors tests the nonce bits validation
+func TestWithInstanceNonceBits_Errors(t *testing.T) {
+	if _, err := NewGenerator(378, WithShardBits(5), WithInstanceNonceBits(7)); !errors.Is(err, ErrNonceBitsTooLarge) {
+		t.Errorf("expected ErrNonceBitsTooLarge, got %v", err)
+	}
+}
+
+// TestCollisionProbability tests CollisionProbability against the exact probabilities of the birthday problem
+func TestCollisionProbability(t *testing.T) {
+	tests := []struct {
+		instances int
+		nonceBits uint64
+		want      float64
+	}{
+		{instances: 0, nonceBits: 8, want: 0},
+		{instances: 1, nonceBits: 8, want: 0},
+		{instances: 2, nonceBits: 8, want: 0.00390625},
+		{instances: 256, nonceBits: 16, want: 0.3926775},
+		{instances: 1000, nonceBits: 32, want: 0.0001162921},
+		{instances: 77163, nonceBits: 32, want: 0.4999999},
+		{instances: 2, nonceBits: 0, want: 1},
+		{instances: 17, nonceBits: 4, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d instances with %d bits", tt.instances, tt.nonceBits), func(t *testing.T) {
+			got := CollisionProbability(tt.instances, tt.nonceBits)
+			if math.Abs(got-tt.want) > tt.want/100 {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
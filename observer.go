@@ -0,0 +1,42 @@
+package snowflake
+
+import "time"
+
+// Observer receives ID generation events, e.g. to update metrics
+// Implement it with counters and histograms of your metrics library, e.g. Prometheus, so this module stays dependency
+// free. The methods are called on the goroutine that generates the ID and must be safe for concurrent use
+// Use WithUsageHistogram for the sequence utilization per millisecond
+type Observer interface {
+	// IDGenerated is called for every ID that is handed out
+	IDGenerated()
+	// SequenceExhausted is called when the sequence of a millisecond is exhausted and no ID could be reserved
+	SequenceExhausted()
+	// ClockWaited is called with the duration the generator slept waiting for the clock
+	ClockWaited(d time.Duration)
+}
+
+// WithObserver sets the observer that receives the ID generation events of the generator
+// Without an observer no events are recorded and no time is measured
+func WithObserver(observer Observer) Option {
+	return func(generator *Generator) {
+		generator.observer = observer
+	}
+}
+
+// sleep waits for the clock with the sleep function and reports the time it slept to the observer
+func (g *Generator) sleep() {
+	if g.observer == nil {
+		g.sleepFunc()
+		return
+	}
+	start := time.Now()
+	g.sleepFunc()
+	g.observer.ClockWaited(time.Since(start))
+}
+
+// sequenceExhausted reports an exhausted sequence to the observer
+func (g *Generator) sequenceExhausted() {
+	if g.observer != nil {
+		g.observer.SequenceExhausted()
+	}
+}
@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testObserver struct {
+	generated atomic.Uint64
+	exhausted atomic.Uint64
+	waited    atomic.Uint64
+}
+
+func (o *testObserver) IDGenerated() {
+	o.generated.Add(1)
+}
+
+func (o *testObserver) SequenceExhausted() {
+	o.exhausted.Add(1)
+}
+
+func (o *testObserver) ClockWaited(time.Duration) {
+	o.waited.Add(1)
+}
+
+// TestWithObserver tests that the observer receives the generated IDs, the exhausted sequences and the clock waits
+func TestWithObserver(t *testing.T) {
+	observer := &testObserver{}
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithObserver(observer))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485447
+	})
+	generator.sleepFunc = func() {
+		generator.SetTimeFunc(func() uint64 {
+			return 367597485448
+		})
+	}
+
+	for i := uint64(0); i <= generator.sequenceMask; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+		return
+	}
+	if _, err = generator.BlockingNextID(context.Background()); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	if got := observer.generated.Load(); got != generator.sequenceMask+2 {
+		t.Errorf("expected %v, got %v", generator.sequenceMask+2, got)
+	}
+	// NextID and the first attempt of BlockingNextID both found the sequence exhausted
+	if got := observer.exhausted.Load(); got != 2 {
+		t.Errorf("expected 2, got %v", got)
+	}
+	if got := observer.waited.Load(); got != 1 {
+		t.Errorf("expected 1, got %v", got)
+	}
+}
+
+// TestGenerator_NextID_WithoutObserverAllocations tests that NextID does not allocate without an observer
+func TestGenerator_NextID_WithoutObserverAllocations(t *testing.T) {
+	generator, err := NewGenerator(378, WithDriftNoWait(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if allocs := testing.AllocsPerRun(1000, func() {
+		_, _ = generator.NextID()
+	}); allocs != 0 {
+		t.Errorf("expected 0, got %v", allocs)
+	}
+}
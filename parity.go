@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+var (
+	// ErrParityMismatch is returned when the parity of an ID does not match its parity digit
+	ErrParityMismatch = errors.New("parity mismatch")
+)
+
+// parity returns 1 if the number of set bits in the ID is odd, and 0 otherwise
+func (id ID) parity() byte {
+	return byte(bits.OnesCount64(uint64(id)) & 1)
+}
+
+// WithParity returns the lower case hex string of the snowflake ID followed by a parity digit, which is always 17
+// characters. The parity digit is 1 if the number of set bits in the ID is odd, and 0 otherwise, so the ID and the
+// parity digit together always have an even number of set bits
+// This detects any single bit flip in the ID or the parity digit, and any odd number of bit flips. An even number of
+// bit flips is not detected, use a checksum when that matters
+func (id ID) WithParity() string {
+	return id.LowerHexString() + string('0'+id.parity())
+}
+
+// ParseWithParity returns the snowflake ID from a string as returned by WithParity, upper case hex is accepted
+// Returns ErrParityMismatch if the parity digit does not match the ID, which means the value was corrupted
+func ParseWithParity(s string) (ID, error) {
+	if len(s) != 17 {
+		return 0, fmt.Errorf("%w: %q must be 17 characters", ErrInvalidID, s)
+	}
+	var value uint64
+	for i := 0; i < 16; i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c -= 'a' - 10
+		case c >= 'A' && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, fmt.Errorf("%w: %q contains invalid hex digit %q", ErrInvalidID, s, s[i])
+		}
+		value = value<<4 | uint64(c)
+	}
+	if s[16] != '0' && s[16] != '1' {
+		return 0, fmt.Errorf("%w: %q has invalid parity digit %q", ErrInvalidID, s, s[16])
+	}
+	id := ID(value)
+	if id.parity() != s[16]-'0' {
+		return 0, fmt.Errorf("%w: %q", ErrParityMismatch, s)
+	}
+	return id, nil
+}
@@ -0,0 +1,80 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestID_WithParity tests that the parity digit makes the number of set bits even
+func TestID_WithParity(t *testing.T) {
+	tests := []struct {
+		id       ID
+		expected string
+	}{
+		{id: 0, expected: "00000000000000000"},
+		{id: 1, expected: "00000000000000011"},
+		{id: 3, expected: "00000000000000030"},
+		{id: 0x123456789ABCDEF0, expected: "123456789abcdef00"},
+		{id: 0xFFFFFFFFFFFFFFFF, expected: "ffffffffffffffff0"},
+		{id: 0x7FFFFFFFFFFFFFFF, expected: "7fffffffffffffff1"},
+	}
+	for _, tt := range tests {
+		if got := tt.id.WithParity(); got != tt.expected {
+			t.Errorf("expected %v, got %v", tt.expected, got)
+			return
+		}
+		id, err := ParseWithParity(tt.expected)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if id != tt.id {
+			t.Errorf("expected %v, got %v", tt.id, id)
+			return
+		}
+	}
+}
+
+// TestParseWithParity_SingleBitFlip tests that every single bit flip in the ID or the parity digit is detected
+func TestParseWithParity_SingleBitFlip(t *testing.T) {
+	id := ID(0x123456789ABCDEF0)
+	for bit := 0; bit < 64; bit++ {
+		flipped := (id ^ ID(1)<<bit).LowerHexString() + id.WithParity()[16:]
+		if _, err := ParseWithParity(flipped); !errors.Is(err, ErrParityMismatch) {
+			t.Errorf("expected %v for bit %d, got %v", ErrParityMismatch, bit, err)
+			return
+		}
+	}
+	if _, err := ParseWithParity(id.LowerHexString() + "1"); !errors.Is(err, ErrParityMismatch) {
+		t.Errorf("expected %v, got %v", ErrParityMismatch, err)
+	}
+}
+
+// TestParseWithParity_Errors tests that malformed strings are rejected
+func TestParseWithParity_Errors(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected error
+	}{
+		{s: "", expected: ErrInvalidID},
+		{s: "123456789abcdef0", expected: ErrInvalidID},
+		{s: "123456789abcdef000", expected: ErrInvalidID},
+		{s: "123456789abcdeg00", expected: ErrInvalidID},
+		{s: "123456789abcdef02", expected: ErrInvalidID},
+		{s: "123456789ABCDEF01", expected: ErrParityMismatch},
+	}
+	for _, tt := range tests {
+		if _, err := ParseWithParity(tt.s); !errors.Is(err, tt.expected) {
+			t.Errorf("expected %v for %q, got %v", tt.expected, tt.s, err)
+			return
+		}
+	}
+	id, err := ParseWithParity("123456789ABCDEF00")
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if id != 0x123456789ABCDEF0 {
+		t.Errorf("expected %v, got %v", ID(0x123456789ABCDEF0), id)
+	}
+}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var (
+	// ErrEmptyPool is returned when a pool is created without machine IDs
+	ErrEmptyPool = errors.New("pool has no machine IDs")
+)
+
+// Pool generates IDs with a generator per machine ID, which multiplies the number of IDs per millisecond by the
+// number of machine IDs
+// The IDs are unique because every generator has a distinct machine ID, they are not ordered across generators
+// within a millisecond
+// All methods are safe for concurrent use
+type Pool struct {
+	generators []*Generator
+	next       atomic.Uint64
+}
+
+// NewPool creates a pool with a generator for each machine ID
+// opts are applied to every generator, the machine IDs are checked after the generators are created, so a machine ID
+// provider in opts that gives the generators the same machine ID is rejected as well
+// Returns ErrEmptyPool without machine IDs, ErrDuplicateMachineID if a machine ID is used more than once and an error
+// if a generator cannot be created
+func NewPool(machineIDs []uint64, opts ...Option) (*Pool, error) {
+	if len(machineIDs) == 0 {
+		return nil, ErrEmptyPool
+	}
+	seen := make(map[uint64]struct{}, len(machineIDs))
+	p := &Pool{generators: make([]*Generator, 0, len(machineIDs))}
+	for _, id := range machineIDs {
+		g, err := NewGenerator(id, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[g.MachineID()]; ok {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateMachineID, g.MachineID())
+		}
+		seen[g.MachineID()] = struct{}{}
+		p.generators = append(p.generators, g)
+	}
+	return p, nil
+}
+
+// NextID generates a new snowflake ID with the generators in round-robin order
+// When the sequence of a generator is exhausted the next generator is tried, so ErrOutOfSequence is only returned
+// when the sequence of every generator is exhausted
+// Returns the error of NextID of the last generator that was tried
+func (p *Pool) NextID() (ID, error) {
+	start := p.next.Add(1) - 1
+	var err error
+	for i := uint64(0); i < uint64(len(p.generators)); i++ {
+		var id ID
+		id, err = p.generators[(start+i)%uint64(len(p.generators))].NextID()
+		if !errors.Is(err, ErrOutOfSequence) {
+			return id, err
+		}
+	}
+	return 0, err
+}
+
+// Generators returns the generators of the pool in the order of the machine IDs
+func (p *Pool) Generators() []*Generator {
+	return append([]*Generator(nil), p.generators...)
+}
@@ -0,0 +1,107 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestNewPool tests that NewPool rejects empty and duplicate machine IDs and invalid options
+func TestNewPool(t *testing.T) {
+	tests := []struct {
+		name       string
+		machineIDs []uint64
+		opts       []Option
+		want       error
+	}{
+		{name: "valid", machineIDs: []uint64{1, 2, 3}},
+		{name: "empty", want: ErrEmptyPool},
+		{name: "duplicate", machineIDs: []uint64{1, 2, 1}, want: ErrDuplicateMachineID},
+		{name: "machine ID too large", machineIDs: []uint64{1, 1024}, want: ErrMachineIDTooLarge},
+		{name: "provider", machineIDs: []uint64{1, 2, 3}, opts: []Option{WithMachineIDProvider(
+			func(ctx context.Context) (uint64, error) { return 7, nil })}, want: ErrDuplicateMachineID},
+		{name: "invalid option", machineIDs: []uint64{1}, opts: []Option{WithMachineIDBits(0)}, want: ErrMachineBitsTooSmall},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := NewPool(tt.machineIDs, tt.opts...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+				return
+			}
+			if err == nil && len(pool.Generators()) != len(tt.machineIDs) {
+				t.Errorf("expected %v generators, got %v", len(tt.machineIDs), len(pool.Generators()))
+			}
+		})
+	}
+}
+
+// TestPool_NextID tests that the pool moves on to the next generator when a sequence is exhausted and returns
+// ErrOutOfSequence when all are exhausted
+func TestPool_NextID(t *testing.T) {
+	// With 20 machine ID bits there are four sequence numbers per millisecond
+	pool, err := NewPool([]uint64{1, 2, 3}, WithEpoch(time.UnixMilli(0)), WithMachineIDBits(20))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	for _, g := range pool.Generators() {
+		g.SetTimeFunc(func() uint64 {
+			return 367597485448
+		})
+	}
+	machineIDs := map[uint64]int{}
+	for i := 0; i < 12; i++ {
+		id, err := pool.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		machineIDs[pool.generators[0].DecodeID(id).MachineID]++
+	}
+	for machineID := uint64(1); machineID <= 3; machineID++ {
+		if machineIDs[machineID] != 4 {
+			t.Errorf("expected 4 IDs of machine ID %v, got %v", machineID, machineIDs)
+		}
+	}
+	if _, err = pool.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+}
+
+// TestPool_NextID_Concurrent tests that the pool generates unique IDs when called concurrently
+func TestPool_NextID_Concurrent(t *testing.T) {
+	pool, err := NewPool([]uint64{1, 2, 3, 4})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	const goroutines = 8
+	const count = 10000
+	results := make(chan []ID, goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			ids := make([]ID, 0, count)
+			for len(ids) < count {
+				id, err := pool.NextID()
+				if err == nil {
+					ids = append(ids, id)
+				}
+			}
+			results <- ids
+		}()
+	}
+
+	seen := make(map[ID]struct{}, goroutines*count)
+	for i := 0; i < goroutines; i++ {
+		for _, id := range <-results {
+			if _, ok := seen[id]; ok {
+				t.Errorf("duplicate ID %v", uint64(id))
+				return
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
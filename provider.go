@@ -0,0 +1,34 @@
+package snowflake
+
+import "context"
+
+// MachineIDProvider returns the machine ID of the generator, for example from a coordination service
+// It should return when the context is done, NewGeneratorContext does not wait for it after that
+type MachineIDProvider func(ctx context.Context) (uint64, error)
+
+// WithMachineIDProvider sets a provider that is called by NewGeneratorContext to get the machine ID
+// The machine ID of the provider replaces the machine ID that is passed to the constructor
+func WithMachineIDProvider(provider MachineIDProvider) Option {
+	return func(generator *Generator) {
+		generator.provider = provider
+	}
+}
+
+// provideMachineID calls the provider and returns its result, or the error of the context if it is done first
+func provideMachineID(ctx context.Context, provider MachineIDProvider) (uint64, error) {
+	type result struct {
+		id  uint64
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		id, err := provider(ctx)
+		done <- result{id: id, err: err}
+	}()
+	select {
+	case r := <-done:
+		return r.id, r.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
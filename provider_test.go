@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestNewGeneratorContext tests that the machine ID of the provider is used
+func TestNewGeneratorContext(t *testing.T) {
+	provider := func(ctx context.Context) (uint64, error) {
+		return 378, nil
+	}
+	generator, err := NewGeneratorContext(context.Background(), 0, WithMachineIDProvider(provider))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if generator.MachineID() != 378 {
+		t.Errorf("expected 378, got %v", generator.MachineID())
+	}
+}
+
+// TestNewGeneratorContext_Errors tests the errors of the provider and the context
+func TestNewGeneratorContext_Errors(t *testing.T) {
+	errProvider := errors.New("provider failed")
+	tests := []struct {
+		name     string
+		provider MachineIDProvider
+		wantErr  error
+	}{
+		{
+			name: "provider error",
+			provider: func(ctx context.Context) (uint64, error) {
+				return 0, errProvider
+			},
+			wantErr: errProvider,
+		},
+		{
+			name: "machine ID too large",
+			provider: func(ctx context.Context) (uint64, error) {
+				return 1024, nil
+			},
+			wantErr: ErrMachineIDTooLarge,
+		},
+		{
+			name: "blocking provider",
+			provider: func(ctx context.Context) (uint64, error) {
+				time.Sleep(time.Second)
+				return 1, nil
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if _, err := NewGeneratorContext(ctx, 0, WithMachineIDProvider(tt.provider)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package snowflake
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidQuantization is returned when the timestamp quantization is not a positive whole number of milliseconds
+	ErrInvalidQuantization = errors.New("timestamp quantization must be a positive whole number of milliseconds")
+)
+
+// WithTimestampQuantization rounds the timestamp of the IDs down to a multiple of d since the Unix epoch, for example
+// to 100ms, so the IDs do not reveal the exact time of the activity they identify
+// All IDs of a bucket share a single sequence, which keeps them unique and ordered within the bucket, but it also
+// limits the generator to one sequence worth of IDs per bucket instead of per millisecond. When the sequence is
+// exhausted drift moves on to the next bucket, otherwise NextID returns ErrOutOfSequence and BlockingNextID blocks
+// until the next bucket. DecodeID, LastTimestamp and the other accessors report the quantized times, the finer time
+// is not recoverable. The clock watchdog sees the quantized time too, so its stall duration must exceed d
+// Returns ErrInvalidQuantization from NewGenerator if d is not a positive whole number of milliseconds, or of the time
+// unit of WithTimeUnit
+func WithTimestampQuantization(d time.Duration) Option {
+	return func(generator *Generator) {
+		generator.quantization = d
+	}
+}
+
+// quantize rounds the time in Unix milliseconds, or time units, down to the timestamp quantization
+func (g *Generator) quantize(unixMilli uint64) uint64 {
+	return unixMilli - unixMilli%g.step
+}
@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestWithTimestampQuantization tests that timestamps are rounded down to the bucket and share its sequence
+func TestWithTimestampQuantization(t *testing.T) {
+	// With 20 machine ID bits there are four sequence numbers per bucket
+	generator, err := NewGenerator(5, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second),
+		WithTimestampQuantization(100*time.Millisecond))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	tests := []struct {
+		now       uint64
+		timestamp uint64
+		sequence  uint64
+	}{
+		{now: 367597485448, timestamp: 367597485400, sequence: 0},
+		{now: 367597485449, timestamp: 367597485400, sequence: 1},
+		{now: 367597485499, timestamp: 367597485400, sequence: 2},
+		{now: 367597485500, timestamp: 367597485500, sequence: 0},
+		{now: 367597485501, timestamp: 367597485500, sequence: 1},
+		{now: 367597485502, timestamp: 367597485500, sequence: 2},
+		{now: 367597485503, timestamp: 367597485500, sequence: 3},
+		// The sequence of the bucket is exhausted, drift moves on to the next bucket
+		{now: 367597485504, timestamp: 367597485600, sequence: 0},
+	}
+	for _, tt := range tests {
+		now := tt.now
+		generator.SetTimeFunc(func() uint64 {
+			return now
+		})
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, tt.timestamp, tt.sequence)
+	}
+}
+
+// TestWithTimestampQuantization_OutOfSequence tests that an exhausted bucket is an error without drift
+func TestWithTimestampQuantization_OutOfSequence(t *testing.T) {
+	generator, err := NewGenerator(5, WithMachineIDBits(21), WithTimestampQuantization(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(1800000000100)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		now += 300
+	}
+	if _, err := generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected %v, got %v", ErrOutOfSequence, err)
+		return
+	}
+	now = 1800000001000
+	if _, err := generator.NextID(); err != nil {
+		t.Errorf("expected no error in the next bucket, got %v", err)
+	}
+}
+
+// TestWithTimestampQuantization_Invalid tests that the quantization must be a positive whole number of milliseconds
+func TestWithTimestampQuantization_Invalid(t *testing.T) {
+	tests := []time.Duration{-time.Millisecond, time.Microsecond, 1500 * time.Microsecond}
+	for _, d := range tests {
+		if _, err := NewGenerator(5, WithTimestampQuantization(d)); !errors.Is(err, ErrInvalidQuantization) {
+			t.Errorf("expected %v for %v, got %v", ErrInvalidQuantization, d, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrInvalidRateLimit is returned when a rate limit is zero or negative
+	ErrInvalidRateLimit = errors.New("invalid rate limit")
+)
+
+// RateLimiter limits the rate at which IDs are generated, it can be shared by generators to cap their combined rate
+type RateLimiter struct {
+	perSecond uint64
+	// costMillis and costFraction are the time an ID costs, in milliseconds and 1/perSecond milliseconds
+	costMillis   uint64
+	costFraction uint64
+	mu           sync.Mutex
+	// allowedAt is the Unix time in milliseconds, plus fraction in 1/perSecond milliseconds, of the next allowed ID
+	allowedAt uint64
+	fraction  uint64
+	err       error
+}
+
+// NewRateLimiter creates a rate limiter that allows at most perSecond IDs per second
+// NewGenerator returns ErrInvalidRateLimit for a generator with the rate limiter if perSecond is zero or negative
+func NewRateLimiter(perSecond int) *RateLimiter {
+	if perSecond <= 0 {
+		return &RateLimiter{err: fmt.Errorf("%w: %d IDs per second", ErrInvalidRateLimit, perSecond)}
+	}
+	return &RateLimiter{
+		perSecond:    uint64(perSecond),
+		costMillis:   1000 / uint64(perSecond),
+		costFraction: 1000 % uint64(perSecond),
+	}
+}
+
+// WithRateLimit limits BlockingNextID to generate at most perSecond IDs per second
+// BlockingNextID uses the sleep function to wait until the next ID is allowed, NextID is not limited
+// The limit is not applied in strict mode, because strict mode never blocks
+// NewGenerator returns ErrInvalidRateLimit if perSecond is zero or negative
+func WithRateLimit(perSecond int) Option {
+	return WithSharedRateLimit(NewRateLimiter(perSecond))
+}
+
+// WithSharedRateLimit limits BlockingNextID like WithRateLimit, with a rate limiter that can be shared by generators
+// All generators that share the rate limiter together generate at most its rate, for example the generators of
+// NewSharedClockSet. The generators should read the same clock, because the rate limiter schedules IDs by their time
+func WithSharedRateLimit(limiter *RateLimiter) Option {
+	return func(generator *Generator) {
+		generator.rateLimiter = limiter
+	}
+}
+
+// take reports whether an ID may be generated at the given Unix time in milliseconds
+// It implements a generic cell rate algorithm in which each ID costs 1000/perSecond milliseconds. IDs are allowed as
+// long as they are scheduled within the current millisecond. The schedule is kept as whole milliseconds and a
+// fraction in units of 1/perSecond milliseconds, so it never multiplies the time by the rate and cannot overflow
+func (l *RateLimiter) take(now uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.allowedAt < now {
+		l.allowedAt, l.fraction = now, 0
+	}
+	if l.allowedAt > now {
+		return false
+	}
+	l.allowedAt += l.costMillis
+	l.fraction += l.costFraction
+	if l.fraction >= l.perSecond {
+		l.allowedAt++
+		l.fraction -= l.perSecond
+	}
+	return true
+}
+
+// waitForRateToken blocks until an ID may be generated according to the rate limit
+// Reports whether it had to block
+// Returns the error of canceled when it returns an error while waiting
+func (g *Generator) waitForRateToken(canceled func() error) (bool, error) {
+	if g.rateLimiter == nil || g.rateLimiter.perSecond == 0 {
+		return false, nil
+	}
+	for blocked := false; ; blocked = true {
+		if g.rateLimiter.take(g.millis(g.now())) {
+			return blocked, nil
+		}
+		if err := canceled(); err != nil {
+			return blocked, err
+		}
+		g.sleep()
+	}
+}
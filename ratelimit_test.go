@@ -0,0 +1,158 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestWithRateLimit tests that BlockingNextID paces itself to the configured rate using a fake clock
+func TestWithRateLimit(t *testing.T) {
+	tests := []struct {
+		perSecond int
+		count     int
+		elapsed   uint64
+	}{
+		{perSecond: 100, count: 5, elapsed: 40},
+		{perSecond: 1000, count: 5, elapsed: 4},
+		{perSecond: 2000, count: 6, elapsed: 2},
+		{perSecond: 1, count: 3, elapsed: 2000},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("TestWithRateLimit=%v", tt.perSecond), func(t *testing.T) {
+			generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithRateLimit(tt.perSecond))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			start := uint64(367597485448)
+			now := start
+			generator.SetTimeFunc(func() uint64 {
+				return now
+			})
+			generator.sleepFunc = func() {
+				now++
+			}
+
+			var previousID ID
+			for i := 0; i < tt.count; i++ {
+				id, err := generator.BlockingNextID(context.TODO())
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				if id <= previousID {
+					t.Errorf("expected id to be greater than previous id, got %v", id)
+				}
+				previousID = id
+			}
+			if now-start != tt.elapsed {
+				t.Errorf("expected %vms to have elapsed, got %vms", tt.elapsed, now-start)
+			}
+		})
+	}
+}
+
+// TestWithRateLimit_ContextCanceled tests that a canceled context interrupts the rate limit wait
+func TestWithRateLimit_ContextCanceled(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithRateLimit(1))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	generator.sleepFunc = cancel
+
+	if _, err = generator.BlockingNextID(ctx); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.BlockingNextID(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+// TestWithSharedRateLimit tests that the generators of a shared clock set together stay within a shared rate limit
+func TestWithSharedRateLimit(t *testing.T) {
+	start := uint64(1656432460105)
+	now := start
+	clock := func() time.Time {
+		return time.UnixMilli(int64(now))
+	}
+	generators, err := NewSharedClockSet(clock, DefaultLayout(), []uint64{1, 2, 3},
+		WithEpoch(time.UnixMilli(1288834974657)), WithSharedRateLimit(NewRateLimiter(1000)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	for _, g := range generators {
+		g.sleepFunc = func() {
+			now++
+		}
+	}
+
+	// Each generator alone would generate its 10 IDs within 9ms, together they are limited to one ID per millisecond
+	for i := 0; i < 30; i++ {
+		if _, err := generators[i%len(generators)].BlockingNextID(context.TODO()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if now-start != 29 {
+		t.Errorf("expected 29ms to have elapsed, got %vms", now-start)
+	}
+}
+
+// TestWithRateLimit_Invalid tests that NewGenerator rejects a rate limit that is zero or negative
+func TestWithRateLimit_Invalid(t *testing.T) {
+	for _, perSecond := range []int{0, -1, -1000} {
+		t.Run(fmt.Sprintf("TestWithRateLimit_Invalid=%v", perSecond), func(t *testing.T) {
+			if _, err := NewGenerator(378, WithRateLimit(perSecond)); !errors.Is(err, ErrInvalidRateLimit) {
+				t.Errorf("expected %v, got %v", ErrInvalidRateLimit, err)
+				return
+			}
+			if _, err := NewGenerator(378, WithSharedRateLimit(NewRateLimiter(perSecond))); !errors.Is(err,
+				ErrInvalidRateLimit) {
+				t.Errorf("expected %v, got %v", ErrInvalidRateLimit, err)
+			}
+		})
+	}
+}
+
+// TestWithRateLimit_HighRate tests that a rate of millions of IDs per second at a late time limits BlockingNextID
+// without overflowing the schedule
+func TestWithRateLimit_HighRate(t *testing.T) {
+	for _, perSecond := range []int{20_000_000, math.MaxInt64} {
+		t.Run(fmt.Sprintf("TestWithRateLimit_HighRate=%v", perSecond), func(t *testing.T) {
+			generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithRateLimit(perSecond))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			now := uint64(922337203685)
+			generator.SetTimeFunc(func() uint64 {
+				return now
+			})
+			generator.sleepFunc = func() {
+				now++
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			for i := 0; i < 1000; i++ {
+				if _, err := generator.BlockingNextID(ctx); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+			if now != 922337203685 {
+				t.Errorf("expected no time to have elapsed, got %vms", now-922337203685)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+// idReader is an io.Reader that generates decimal IDs followed by a separator, or binary IDs, as they are read
+type idReader struct {
+	g       *Generator
+	ctx     context.Context
+	sep     byte
+	binary  bool
+	buf     [21]byte
+	pending []byte
+}
+
+// Reader returns an io.Reader that yields decimal IDs, each followed by sep, generating them with BlockingNextID as
+// they are read
+// An ID that does not fit in the buffer of a Read call is kept for the next call, so IDs are never split or lost
+// Read returns io.EOF once the context is canceled and the rest of the current ID is read, a nil context is never
+// canceled
+func (g *Generator) Reader(ctx context.Context, sep byte) io.Reader {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &idReader{g: g, ctx: ctx, sep: sep}
+}
+
+// BinaryReader returns an io.Reader that yields IDs as 8 big-endian bytes without a delimiter, generating them with
+// BlockingNextID as they are read, the stream can be decoded with DecodeBinaryStream
+// The bytes of an ID that do not fit in the buffer of a Read call are kept for the next call, so a buffer of fewer
+// than 8 bytes still reads every byte of every ID
+// Read returns io.EOF once the context is canceled and the rest of the current ID is read, a nil context is never
+// canceled
+func (g *Generator) BinaryReader(ctx context.Context) io.Reader {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &idReader{g: g, ctx: ctx, binary: true}
+}
+
+// Read fills p with IDs, it returns io.EOF once the context is canceled and any other error of BlockingNextID
+func (r *idReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.pending) == 0 {
+			id, err := r.next()
+			if err != nil {
+				if n > 0 {
+					return n, nil
+				}
+				return 0, err
+			}
+			if r.binary {
+				binary.BigEndian.PutUint64(r.buf[:8], uint64(id))
+				r.pending = r.buf[:8]
+			} else {
+				r.pending = append(strconv.AppendUint(r.buf[:0], uint64(id), 10), r.sep)
+			}
+		}
+		c := copy(p[n:], r.pending)
+		r.pending = r.pending[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// next generates the next ID, it returns io.EOF when the context is canceled
+func (r *idReader) next() (ID, error) {
+	if r.ctx.Err() != nil {
+		return 0, io.EOF
+	}
+	id, err := r.g.BlockingNextID(r.ctx)
+	if err != nil && r.ctx.Err() != nil {
+		return 0, io.EOF
+	}
+	return id, err
+}
@@ -0,0 +1,120 @@
+package snowflake
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestGenerator_Reader tests that the Reader yields separated decimal IDs, also when reads split an ID
+func TestGenerator_Reader(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	// A small buffer forces IDs to be split over several Read calls
+	scanner := bufio.NewScanner(bufio.NewReaderSize(generator.Reader(context.Background(), ','), 16))
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		for i, b := range data {
+			if b == ',' {
+				return i + 1, data[:i], nil
+			}
+		}
+		return 0, nil, nil
+	})
+	for sequence := uint64(0); sequence < 100; sequence++ {
+		if !scanner.Scan() {
+			t.Errorf("expected an ID, got %v", scanner.Err())
+			return
+		}
+		id, err := strconv.ParseUint(scanner.Text(), 10, 64)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, ID(id), 367597485448, sequence)
+	}
+}
+
+// TestGenerator_Reader_Canceled tests that the Reader returns io.EOF after the context is canceled and the rest of
+// the current ID is read
+func TestGenerator_Reader_Canceled(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := generator.Reader(ctx, '\n')
+	p := make([]byte, 64)
+	if n, err := r.Read(p); n != len(p) || err != nil {
+		t.Errorf("expected %d bytes and no error, got %d and %v", len(p), n, err)
+	}
+	cancel()
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(rest) > 20 {
+		t.Errorf("expected at most the rest of one ID, got %q", rest)
+	}
+	if _, err = r.Read(p); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+// TestGenerator_BinaryReader tests that the BinaryReader yields big-endian IDs, also when reads are shorter than an ID
+func TestGenerator_BinaryReader(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	r := generator.BinaryReader(context.Background())
+	// Reads of 3 bytes split every ID over several Read calls
+	var stream []byte
+	p := make([]byte, 3)
+	for len(stream) < 100*8 {
+		n, err := r.Read(p)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		stream = append(stream, p[:n]...)
+	}
+	decoder := DecodeBinaryStream(bytes.NewReader(stream[:100*8]))
+	for sequence := uint64(0); sequence < 100; sequence++ {
+		if !decoder.Next() {
+			t.Errorf("expected an ID, got %v", decoder.Err())
+			return
+		}
+		verifyRoundTrip(t, generator, decoder.ID(), 367597485448, sequence)
+	}
+}
+
+// TestGenerator_BinaryReader_Error tests that the BinaryReader returns the error of the generator
+func TestGenerator_BinaryReader_Error(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 0
+	})
+	if _, err = generator.BinaryReader(context.Background()).Read(make([]byte, 8)); !errors.Is(err, ErrTimeBeforeEpoch) {
+		t.Errorf("expected ErrTimeBeforeEpoch, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownLayout is returned when no layout is registered with a name
+	ErrUnknownLayout = errors.New("unknown layout")
+	// ErrLayoutRegistered is returned when a layout is already registered with a name
+	ErrLayoutRegistered = errors.New("layout is already registered")
+)
+
+// namedLayout is a registered layout with the epoch and time unit of the IDs, which are zero for the defaults
+type namedLayout struct {
+	layout Layout
+	epoch  time.Time
+	unit   time.Duration
+}
+
+// layouts holds the registered layouts by name
+var layouts = struct {
+	sync.RWMutex
+	byName map[string]namedLayout
+}{
+	byName: map[string]namedLayout{
+		// Twitter reserves the sign bit, which leaves 41 bits for the timestamp
+		"twitter": {layout: Layout{VersionBit: true, MachineIDBits: 10}, epoch: TwitterEpoch},
+		// Discord splits the 10 machine ID bits in 5 worker and 5 process bits
+		"discord": {layout: Layout{MachineIDBits: 10}, epoch: DiscordEpoch},
+		// Sonyflake has 39 timestamp bits in units of 10ms, followed by 8 sequence bits and 16 machine ID bits
+		"sonyflake": {
+			layout: Layout{TimestampBits: 39, MachineIDBits: 16, Order: OrderTimestampSequenceMachine},
+			epoch:  SonyflakeEpoch,
+			unit:   10 * time.Millisecond,
+		},
+	},
+}
+
+// RegisterLayout registers a layout with a name, so generators can be created by name with NewGeneratorNamed
+// The layouts twitter, discord and sonyflake are registered by default, with the epoch and time unit of their IDs
+// Registered layouts only describe the bits, the epoch is set with WithEpoch
+// Returns an error if the layout is invalid or a layout is already registered with the name
+func RegisterLayout(name string, l Layout) error {
+	if err := l.validate(); err != nil {
+		return err
+	}
+	layouts.Lock()
+	defer layouts.Unlock()
+	if _, ok := layouts.byName[name]; ok {
+		return fmt.Errorf("%w: %q", ErrLayoutRegistered, name)
+	}
+	layouts.byName[name] = namedLayout{layout: l}
+	return nil
+}
+
+// NewGeneratorNamed creates a new snowflake ID generator with the layout registered with the name
+// The built-in layouts also set their epoch and time unit, so the generators generate IDs of that system
+// opts are applied after the layout, so they can override the epoch and time unit
+// Returns an error if no layout is registered with the name or the generator cannot be created
+func NewGeneratorNamed(name string, machineID uint64, opts ...Option) (*Generator, error) {
+	layouts.RLock()
+	l, ok := layouts.byName[name]
+	layouts.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLayout, name)
+	}
+	named := []Option{withLayout(l.layout)}
+	if !l.epoch.IsZero() {
+		named = append(named, WithEpoch(l.epoch))
+	}
+	if l.unit != 0 {
+		named = append(named, WithTimeUnit(l.unit))
+	}
+	return NewGenerator(machineID, append(named, opts...)...)
+}
@@ -0,0 +1,133 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestNewGeneratorNamed tests that generators created by name use the registered layout
+func TestNewGeneratorNamed(t *testing.T) {
+	// The layout is already registered when the test runs more than once
+	err := RegisterLayout("test-v1", Layout{MachineIDBits: 8, ShardBits: 4})
+	if err != nil && !errors.Is(err, ErrLayoutRegistered) {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	tests := []struct {
+		name string
+		want Layout
+	}{
+		{name: "twitter", want: Layout{VersionBit: true, MachineIDBits: 10}},
+		{name: "discord", want: Layout{MachineIDBits: 10}},
+		{name: "sonyflake", want: Layout{TimestampBits: 39, MachineIDBits: 16, Order: OrderTimestampSequenceMachine}},
+		{name: "test-v1", want: Layout{MachineIDBits: 8, ShardBits: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGeneratorNamed(tt.name, 1)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.Layout(); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestNewGeneratorNamed_TwitterEpoch tests the Twitter test vector with the epoch of the registered twitter layout
+func TestNewGeneratorNamed_TwitterEpoch(t *testing.T) {
+	generator, err := NewGeneratorNamed("twitter", 378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 1656432460105
+	})
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if id != 1541815603606036480 {
+		t.Errorf("expected 1541815603606036480, got %v", uint64(id))
+	}
+}
+
+// TestNewGeneratorNamed_Epochs tests that the built-in layouts set the epoch and time unit of their IDs
+func TestNewGeneratorNamed_Epochs(t *testing.T) {
+	tests := []struct {
+		name  string
+		epoch time.Time
+		unit  time.Duration
+	}{
+		{name: "twitter", epoch: TwitterEpoch, unit: time.Millisecond},
+		{name: "discord", epoch: DiscordEpoch, unit: time.Millisecond},
+		{name: "sonyflake", epoch: SonyflakeEpoch, unit: 10 * time.Millisecond},
+		{name: "test-v1", epoch: time.UnixMilli(defaultEpoch), unit: time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGeneratorNamed(tt.name, 1)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if got := generator.Epoch(); !got.Equal(tt.epoch) {
+				t.Errorf("expected %v, got %v", tt.epoch, got)
+				return
+			}
+			if generator.unit != tt.unit {
+				t.Errorf("expected %v, got %v", tt.unit, generator.unit)
+			}
+		})
+	}
+}
+
+// TestNewGeneratorNamed_Sonyflake tests that the sonyflake layout places the sequence above the machine ID and counts
+// the time in units of 10ms since the Sonyflake epoch
+func TestNewGeneratorNamed_Sonyflake(t *testing.T) {
+	generator, err := NewGeneratorNamed("sonyflake", 0xBEEF)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// 2022-06-28T16:07:40.100Z in units of 10ms since the Unix epoch
+	generator.SetTimeFunc(func() uint64 {
+		return 165643246010
+	})
+	elapsed := uint64(165643246010 - SonyflakeEpoch.UnixMilli()/10)
+	for sequence := uint64(0); sequence < 2; sequence++ {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if want := ID(elapsed<<24 | sequence<<16 | 0xBEEF); id != want {
+			t.Errorf("expected %v, got %v", uint64(want), uint64(id))
+			return
+		}
+	}
+	if got := generator.Time(ID(elapsed << 24)); !got.Equal(time.UnixMilli(1656432460100)) {
+		t.Errorf("expected %v, got %v", time.UnixMilli(1656432460100).UTC(), got)
+	}
+}
+
+// TestRegisterLayout_Errors tests the errors of RegisterLayout and NewGeneratorNamed
+func TestRegisterLayout_Errors(t *testing.T) {
+	if err := RegisterLayout("twitter", DefaultLayout()); !errors.Is(err, ErrLayoutRegistered) {
+		t.Errorf("expected ErrLayoutRegistered, got %v", err)
+	}
+	if err := RegisterLayout("invalid", Layout{}); !errors.Is(err, ErrMachineBitsTooSmall) {
+		t.Errorf("expected ErrMachineBitsTooSmall, got %v", err)
+	}
+	if _, err := NewGeneratorNamed("unknown", 1); !errors.Is(err, ErrUnknownLayout) {
+		t.Errorf("expected ErrUnknownLayout, got %v", err)
+	}
+	if _, err := NewGeneratorNamed("discord", 1024, WithEpoch(time.UnixMilli(1420070400000))); !errors.Is(err, ErrMachineIDTooLarge) {
+		t.Errorf("expected ErrMachineIDTooLarge, got %v", err)
+	}
+}
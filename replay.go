@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidWindow is returned when the window of a replay guard is shorter than one millisecond, or the window
+	// of Bucket is shorter than the time unit of the generator
+	ErrInvalidWindow = errors.New("window is too short")
+)
+
+// ReplayGuard rejects IDs that were already seen within a sliding window of their embedded timestamps
+// Seen IDs are kept in buckets per millisecond of their timestamp, the window ends at the latest timestamp seen and
+// buckets that fall out of the window are evicted, so the memory is bounded by the IDs of one window
+// The buckets are evicted from the oldest timestamp, so moving the window only costs time for the buckets it evicts
+// A ReplayGuard is safe for concurrent use
+type ReplayGuard struct {
+	mu         sync.Mutex
+	layout     Layout
+	window     uint64
+	latest     uint64
+	buckets    map[uint64]map[ID]struct{}
+	timestamps timestampHeap
+}
+
+// NewReplayGuard creates a replay guard for IDs with the given layout that remembers IDs for the given window
+// Returns ErrInvalidWindow if the window is shorter than one millisecond
+func NewReplayGuard(layout Layout, window time.Duration) (*ReplayGuard, error) {
+	if window < time.Millisecond {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWindow, window)
+	}
+	return &ReplayGuard{
+		layout:  layout,
+		window:  uint64(window.Milliseconds()),
+		buckets: make(map[uint64]map[ID]struct{}),
+	}, nil
+}
+
+// Seen reports whether the ID is a replay and records it otherwise
+// An ID is a replay when it was seen before within the window, or when its timestamp is before the window, because
+// it can no longer be checked. Time advances with the timestamps of the IDs, a later ID moves the window forward
+func (r *ReplayGuard) Seen(id ID) bool {
+	timestamp := uint64(id) >> r.layout.timestampShift() & r.layout.timestampMask()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if timestamp > r.latest {
+		r.latest = timestamp
+		r.evict()
+	}
+	if r.latest-timestamp >= r.window {
+		return true
+	}
+	bucket, ok := r.buckets[timestamp]
+	if !ok {
+		bucket = make(map[ID]struct{})
+		r.buckets[timestamp] = bucket
+		heap.Push(&r.timestamps, timestamp)
+	}
+	if _, ok := bucket[id]; ok {
+		return true
+	}
+	bucket[id] = struct{}{}
+	return false
+}
+
+// evict removes the buckets that are before the window, starting at the oldest
+func (r *ReplayGuard) evict() {
+	for len(r.timestamps) > 0 && r.latest-r.timestamps[0] >= r.window {
+		delete(r.buckets, heap.Pop(&r.timestamps).(uint64))
+	}
+}
+
+// timestampHeap is a min-heap of the timestamps of the buckets of a replay guard
+type timestampHeap []uint64
+
+// Len returns the number of timestamps
+func (h timestampHeap) Len() int { return len(h) }
+
+// Less reports whether the timestamp at i is older than the timestamp at j
+func (h timestampHeap) Less(i, j int) bool { return h[i] < h[j] }
+
+// Swap swaps the timestamps at i and j
+func (h timestampHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+// Push adds a timestamp, use heap.Push instead
+func (h *timestampHeap) Push(x interface{}) { *h = append(*h, x.(uint64)) }
+
+// Pop removes the last timestamp, use heap.Pop instead
+func (h *timestampHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestReplayGuard_Seen tests that the replay guard rejects replays within its window and IDs before its window
+func TestReplayGuard_Seen(t *testing.T) {
+	guard, err := NewReplayGuard(DefaultLayout(), 10*time.Millisecond)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	id := func(timestamp uint64, sequence uint64) ID {
+		return ID(timestamp<<22 | 378<<12 | sequence)
+	}
+	tests := []struct {
+		name string
+		id   ID
+		want bool
+	}{
+		{name: "first ID", id: id(100, 0), want: false},
+		{name: "replay", id: id(100, 0), want: true},
+		{name: "next sequence", id: id(100, 1), want: false},
+		{name: "earlier timestamp within the window", id: id(95, 0), want: false},
+		{name: "window moves forward", id: id(109, 0), want: false},
+		{name: "replay at the start of the window", id: id(100, 1), want: true},
+		{name: "window moves past the first IDs", id: id(110, 0), want: false},
+		{name: "ID before the window", id: id(100, 2), want: true},
+		{name: "replay after the window moved", id: id(109, 0), want: true},
+	}
+	for _, tt := range tests {
+		if got := guard.Seen(tt.id); got != tt.want {
+			t.Errorf("%v: expected %v, got %v", tt.name, tt.want, got)
+			return
+		}
+	}
+	if len(guard.buckets) != 2 || len(guard.timestamps) != 2 {
+		t.Errorf("expected the buckets before the window to be evicted, got %v buckets and %v timestamps",
+			len(guard.buckets), len(guard.timestamps))
+	}
+}
+
+// TestNewReplayGuard_InvalidWindow tests that NewReplayGuard rejects windows shorter than one millisecond
+func TestNewReplayGuard_InvalidWindow(t *testing.T) {
+	for _, window := range []time.Duration{0, -time.Second, 999 * time.Microsecond} {
+		if _, err := NewReplayGuard(DefaultLayout(), window); !errors.Is(err, ErrInvalidWindow) {
+			t.Errorf("%v: expected %v, got %v", window, ErrInvalidWindow, err)
+		}
+	}
+}
+
+// TestReplayGuard_Seen_Concurrent tests that concurrent replays of generated IDs are rejected exactly once
+func TestReplayGuard_Seen_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ids := make([]ID, 1000)
+	for i := range ids {
+		if ids[i], err = generator.BlockingNextID(context.TODO()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+
+	guard, err := NewReplayGuard(generator.Layout(), time.Hour)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var mu sync.Mutex
+	accepted := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, id := range ids {
+				if !guard.Seen(id) {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if accepted != len(ids) {
+		t.Errorf("expected %v accepted IDs, got %v", len(ids), accepted)
+	}
+}
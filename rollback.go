@@ -0,0 +1,32 @@
+package snowflake
+
+import "time"
+
+// WithClockRollbackTolerance waits for the clock to catch up when it moved backwards by at most d in strict mode,
+// instead of returning ErrClockMovedBackwards
+// The generator sleeps until the next millisecond and reads the clock again until it is no longer behind the last
+// generated ID, which takes up to d, so NextID blocks too in that case. A larger step backwards still returns
+// ErrClockMovedBackwards with the delta. Without strict mode a clock that moved backwards continues the sequence of
+// the last generated ID instead, so the tolerance has no effect
+func WithClockRollbackTolerance(d time.Duration) Option {
+	return func(generator *Generator) {
+		generator.tolerance = d
+	}
+}
+
+// waitForClock sleeps until the clock catches up with the last generated ID when it is behind by at most the
+// rollback tolerance, and returns the milliseconds since the epoch of the clock after waiting
+func (g *Generator) waitForClock(now uint64, timeFunc TimeFunc) (uint64, error) {
+	for {
+		lastTime := g.currentID.Load() >> g.stateShift
+		if lastTime <= now || lastTime-now > g.units(g.tolerance) {
+			return now, nil
+		}
+		g.lastCallBlocked.Store(true)
+		g.sleep()
+		var err error
+		if now, err = g.elapsed(timeFunc()); err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestWithClockRollbackTolerance tests that a small step backwards waits for the clock and a large one is an error
+func TestWithClockRollbackTolerance(t *testing.T) {
+	clock := NewManualClock(time.UnixMilli(367597485448))
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithStrict(), WithClockInterface(clock),
+		WithClockRollbackTolerance(5*time.Millisecond))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err := generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	// Within the tolerance the generator sleeps until the clock is back at the last generated ID
+	clock.Set(time.UnixMilli(367597485445))
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, generator, id, 367597485448, 1)
+	if !generator.LastCallBlocked() {
+		t.Errorf("expected the call to block")
+	}
+
+	clock.Set(time.UnixMilli(367597485442))
+	_, err = generator.NextID()
+	if !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("expected %v, got %v", ErrClockMovedBackwards, err)
+		return
+	}
+	if want := "clock moved backwards: 6ms behind the last generated ID"; err.Error() != want {
+		t.Errorf("expected %v, got %v", want, err)
+	}
+}
+
+// TestWithClockRollbackTolerance_NotStrict tests that the tolerance has no effect without strict mode
+func TestWithClockRollbackTolerance_NotStrict(t *testing.T) {
+	now := uint64(367597485448)
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithClockRollbackTolerance(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	generator.sleepFunc = func() {
+		t.Errorf("expected no sleep")
+	}
+	if _, err := generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now -= 10
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// The ID continues the sequence of the last millisecond instead
+	verifyRoundTrip(t, generator, id, 367597485448, 1)
+}
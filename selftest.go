@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrSelfTestFailed is returned when SelfTest finds a duplicate or decreasing ID
+	ErrSelfTestFailed = errors.New("self-test failed")
+)
+
+// selfTestGoroutines and selfTestIDs are the number of goroutines of SelfTest and the number of IDs each generates
+const (
+	selfTestGoroutines = 4
+	selfTestIDs        = 1000
+)
+
+// SelfTest generates a few thousand IDs with the layout and machine ID of the generator on a fake clock and
+// verifies that they are unique and that the IDs of every goroutine increase strictly, as a check at startup
+// The IDs are generated concurrently on a clock that advances every few calls and regularly moves back, so they cross
+// millisecond boundaries, exhaust the sequence and drift. The generator itself is not used, its state is unchanged
+// The IDs are compared by timestamp and sequence, so the check also holds for the spread layout
+// Returns ErrSelfTestFailed wrapped with the offending ID, or the error that generating an ID returned
+func (g *Generator) SelfTest() error {
+	generator, err := NewGenerator(g.machineID.Load(), withLayout(g.layout), WithEpochMillis(0),
+		WithDriftNoWait(time.Hour))
+	if err != nil {
+		return err
+	}
+	var calls atomic.Uint64
+	generator.SetTimeFunc(func() uint64 {
+		call := calls.Add(1)
+		if call%97 == 0 {
+			// Move the clock back to exercise the clock rollback handling
+			return 1<<20 + call/7 - 3
+		}
+		return 1<<20 + call/7
+	})
+
+	var wg sync.WaitGroup
+	results := make([][]DecodedID, selfTestGoroutines)
+	errs := make([]error, selfTestGoroutines)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < selfTestIDs; j++ {
+				id, err := generator.NextID()
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				results[i] = append(results[i], generator.DecodeID(id))
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	seen := make(map[uint64]struct{}, selfTestGoroutines*selfTestIDs)
+	for _, decoded := range results {
+		for j, d := range decoded {
+			if _, ok := seen[d.ID]; ok {
+				return fmt.Errorf("%w: duplicate ID %d", ErrSelfTestFailed, d.ID)
+			}
+			seen[d.ID] = struct{}{}
+			if j == 0 {
+				continue
+			}
+			previous := decoded[j-1]
+			if d.Timestamp < previous.Timestamp || d.Timestamp == previous.Timestamp && d.Sequence <= previous.Sequence {
+				return fmt.Errorf("%w: ID %d is not after ID %d", ErrSelfTestFailed, d.ID, previous.ID)
+			}
+		}
+	}
+	return nil
+}
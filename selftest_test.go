@@ -0,0 +1,41 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGenerator_SelfTest tests that the self-test passes for common layouts within a second and leaves the state of
+// the generator unchanged
+func TestGenerator_SelfTest(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "default"},
+		{name: "twitter", opts: []Option{WithVersionBit(1)}},
+		{name: "two sequence numbers per millisecond", opts: []Option{WithMachineIDBits(21)}},
+		{name: "spread", opts: []Option{WithSpreadLayout(), WithInstanceNonceBits(4)}},
+		{name: "single node", opts: []Option{WithSingleNodeLayout()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(0, tt.opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			start := time.Now()
+			if err = generator.SelfTest(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("expected the self-test to take less than a second, got %v", elapsed)
+			}
+			if !generator.LastTimestamp().IsZero() {
+				t.Errorf("expected the state of the generator to be unchanged, got %v", generator.LastTimestamp())
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// lockedRand is a random source that is safe for concurrent use by all generators that share an option
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// Int63n returns a random number in [0, n) while holding the lock
+func (l *lockedRand) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63n(n)
+}
+
+// WithRandomSequenceStart starts the sequence of every new millisecond at a random offset drawn from r, instead of at
+// zero, so the low bits of the first IDs of a millisecond are not predictable and IDs spread evenly over shards that
+// are chosen by the ID modulo N at low throughput
+// The sequence still increases within the millisecond, when it reaches the last sequence number the generator moves
+// on to the next millisecond like any exhausted sequence, so fewer IDs fit in a millisecond that starts late.
+// Reservations of several IDs start early enough to fit in the millisecond, a whole millisecond starts at zero
+// r is only used while holding a lock that is shared by every generator the option is applied to, such as the
+// generators of NewPool, so r must not be used elsewhere. It is ignored with WithSequenceStrategy and a nil r starts
+// at zero
+func WithRandomSequenceStart(r *rand.Rand) Option {
+	var random *lockedRand
+	if r != nil {
+		random = &lockedRand{r: r}
+	}
+	return func(generator *Generator) {
+		generator.random = random
+	}
+}
+
+// sequenceStart returns the sequence a new millisecond starts at, which leaves room for n sequence numbers
+func (g *Generator) sequenceStart(n uint64) uint64 {
+	if g.random == nil || n > g.sequenceMask {
+		return 0
+	}
+	return uint64(g.random.Int63n(int64(g.sequenceMask + 2 - n)))
+}
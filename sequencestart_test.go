@@ -0,0 +1,123 @@
+package snowflake
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWithRandomSequenceStart tests that every millisecond starts at a random sequence and the IDs stay unique and
+// strictly increasing
+func TestWithRandomSequenceStart(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithRandomSequenceStart(rand.New(rand.NewSource(1))))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var ids []ID
+	starts := map[uint64]bool{}
+	for now := uint64(367597485448); now < 367597485548; now++ {
+		now := now
+		generator.SetTimeFunc(func() uint64 {
+			return now
+		})
+		for i := 0; i < 3; i++ {
+			id, err := generator.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if i == 0 {
+				starts[generator.DecodeID(id).Sequence] = true
+			}
+			ids = append(ids, id)
+		}
+	}
+	if err = CheckMonotonicUnique(ids); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(starts) < 90 {
+		t.Errorf("expected at least 90 different start sequences, got %v", len(starts))
+	}
+}
+
+// TestWithRandomSequenceStart_Exhausted tests that the sequence of a millisecond that starts at a random offset ends
+// at the last sequence number and then requires the next millisecond
+func TestWithRandomSequenceStart_Exhausted(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithRandomSequenceStart(rand.New(rand.NewSource(1))))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	first, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	start := generator.DecodeID(first).Sequence
+	if start == 0 {
+		t.Errorf("expected a random start sequence, got %v", start)
+		return
+	}
+	for sequence := start + 1; sequence <= generator.sequenceMask; sequence++ {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, 367597485448, sequence)
+	}
+	if _, err = generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected ErrOutOfSequence, got %v", err)
+	}
+
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485449
+	})
+	a, b, err := generator.NextIDPair()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if generator.DecodeID(b).Sequence != generator.DecodeID(a).Sequence+1 {
+		t.Errorf("expected consecutive sequences, got %v and %v", generator.DecodeID(a), generator.DecodeID(b))
+	}
+	ids, err := generator.ClaimMillisecond()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if err = CheckMonotonicUnique(append([]ID{a, b}, ids...)); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+// TestWithRandomSequenceStart_Pool tests that the generators of a pool share the random source of the option safely,
+// run it with -race
+func TestWithRandomSequenceStart_Pool(t *testing.T) {
+	pool, err := NewPool([]uint64{1, 2, 3, 4}, WithEpoch(time.UnixMilli(0)),
+		WithRandomSequenceStart(rand.New(rand.NewSource(1))))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for _, generator := range pool.Generators() {
+		wg.Add(1)
+		go func(generator *Generator) {
+			defer wg.Done()
+			for now := uint64(367597485448); now < 367597485548; now++ {
+				if _, err := generator.NextIDWithTime(time.UnixMilli(int64(now))); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+		}(generator)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,90 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"runtime"
+)
+
+var (
+	// ErrTooManyShards is returned when the shard index needs more bits than the machine ID has
+	ErrTooManyShards = errors.New("too many shards for the machine ID bits")
+)
+
+// ShardedGenerator generates IDs with a generator per shard so concurrent callers rarely contend on the same state
+// The lowest ShardBits bits of the machine ID hold the shard index and the machine ID occupies the bits above them,
+// so every shard has a distinct machine ID and the IDs are unique across shards and across machines. The IDs are
+// not ordered across shards within a millisecond
+// All methods are safe for concurrent use
+type ShardedGenerator struct {
+	pool      *Pool
+	shardBits uint64
+}
+
+// NewShardedGenerator creates a generator with the given number of shards, or one shard per GOMAXPROCS if shards is
+// zero or less
+// The shard index takes bits.Len(shards-1) of the machine ID bits, 2 shards take 1 bit and 8 shards take 3 bits, so
+// machineID must fit in the machine ID bits that remain
+// opts are applied to every shard, a machine ID provider in opts replaces the machine IDs of the shards and returns
+// ErrDuplicateMachineID when it gives two shards the same machine ID
+// Returns ErrTooManyShards if the shard index needs more bits than the machine ID has and ErrMachineIDTooLarge if
+// the machine ID does not fit in the remaining bits
+func NewShardedGenerator(machineID uint64, shards int, opts ...Option) (*ShardedGenerator, error) {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	layout, err := optionLayout(opts)
+	if err != nil {
+		return nil, err
+	}
+	machineIDBits := layout.MachineIDBits
+	shardBits := uint64(bits.Len(uint(shards - 1)))
+	if shardBits > machineIDBits {
+		return nil, fmt.Errorf("%w: %d shards need %d bits, the machine ID has %d", ErrTooManyShards, shards, shardBits,
+			machineIDBits)
+	}
+	if machineID > layout.machineIDMask()>>shardBits {
+		return nil, machineIDTooLarge(machineID, machineIDBits-shardBits)
+	}
+
+	machineIDs := make([]uint64, shards)
+	for i := range machineIDs {
+		machineIDs[i] = machineID<<shardBits | uint64(i)
+	}
+	pool, err := NewPool(machineIDs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ShardedGenerator{pool: pool, shardBits: shardBits}, nil
+}
+
+// optionLayout returns the layout that opts set, without creating a generator, so the machine ID provider, the
+// startup sleep and the other work of NewGenerator are only done for the shards
+func optionLayout(opts []Option) (Layout, error) {
+	g := &Generator{layout: DefaultLayout()}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if err := g.layout.validate(); err != nil {
+		return Layout{}, err
+	}
+	return g.layout, nil
+}
+
+// NextID generates a new snowflake ID with the shards in round-robin order
+// When the sequence of a shard is exhausted the next shard is tried, so ErrOutOfSequence is only returned when the
+// sequence of every shard is exhausted
+func (s *ShardedGenerator) NextID() (ID, error) {
+	return s.pool.NextID()
+}
+
+// Shards returns the generators of the shards in the order of the shard index
+func (s *ShardedGenerator) Shards() []*Generator {
+	return s.pool.Generators()
+}
+
+// ShardBits returns the number of machine ID bits used for the shard index
+func (s *ShardedGenerator) ShardBits() uint64 {
+	return s.shardBits
+}
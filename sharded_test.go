@@ -0,0 +1,147 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// TestNewShardedGenerator tests the shard bits and the validation of the machine ID bits
+func TestNewShardedGenerator(t *testing.T) {
+	tests := []struct {
+		name          string
+		machineID     uint64
+		shards        int
+		opts          []Option
+		wantShardBits uint64
+		wantErr       error
+	}{
+		{name: "one shard", machineID: 1023, shards: 1, wantShardBits: 0},
+		{name: "two shards", machineID: 511, shards: 2, wantShardBits: 1},
+		{name: "five shards", machineID: 127, shards: 5, wantShardBits: 3},
+		{name: "eight shards", machineID: 127, shards: 8, wantShardBits: 3},
+		{name: "all machine ID bits", machineID: 0, shards: 1024, wantShardBits: 10},
+		{name: "machine ID too large", machineID: 128, shards: 8, wantErr: ErrMachineIDTooLarge},
+		{name: "too many shards", shards: 1025, wantErr: ErrTooManyShards},
+		{name: "too many shards for the bits", shards: 5, opts: []Option{WithMachineIDBits(2)}, wantErr: ErrTooManyShards},
+		{name: "invalid option", shards: 2, opts: []Option{WithMachineIDBits(0)}, wantErr: ErrInvalidMachineIDBits},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewShardedGenerator(tt.machineID, tt.shards, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if g.ShardBits() != tt.wantShardBits {
+				t.Errorf("expected %v, got %v", tt.wantShardBits, g.ShardBits())
+				return
+			}
+			for i, shard := range g.Shards() {
+				want := tt.machineID<<tt.wantShardBits | uint64(i)
+				if got := shard.MachineID(); got != want {
+					t.Errorf("expected %v, got %v", want, got)
+					return
+				}
+			}
+		})
+	}
+}
+
+// TestNewShardedGenerator_Provider tests that the options run once per shard and that a machine ID provider that
+// gives the shards the same machine ID is rejected
+func TestNewShardedGenerator_Provider(t *testing.T) {
+	var calls int
+	provider := func(ctx context.Context) (uint64, error) {
+		calls++
+		return 91, nil
+	}
+	if _, err := NewShardedGenerator(1, 4, WithMachineIDProvider(provider)); !errors.Is(err, ErrDuplicateMachineID) {
+		t.Errorf("expected %v, got %v", ErrDuplicateMachineID, err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %v", calls)
+	}
+
+	calls = 0
+	g, err := NewShardedGenerator(1, 1, WithMachineIDProvider(provider))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %v", calls)
+	}
+	if got := g.Shards()[0].MachineID(); got != 91 {
+		t.Errorf("expected 91, got %v", got)
+	}
+}
+
+// TestNewShardedGenerator_GOMAXPROCS tests that a shard is created per GOMAXPROCS when shards is zero
+func TestNewShardedGenerator_GOMAXPROCS(t *testing.T) {
+	g, err := NewShardedGenerator(0, 0)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got, want := len(g.Shards()), runtime.GOMAXPROCS(0); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestShardedGenerator_NextID_Concurrent tests that the shards generate unique IDs when called concurrently
+func TestShardedGenerator_NextID_Concurrent(t *testing.T) {
+	g, err := NewShardedGenerator(5, 8)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	const goroutines = 16
+	const count = 10000
+	results := make(chan []ID, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]ID, 0, count)
+			for len(ids) < count {
+				id, err := g.NextID()
+				if err == nil {
+					ids = append(ids, id)
+				}
+			}
+			results <- ids
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[ID]struct{}, goroutines*count)
+	shards := map[uint64]struct{}{}
+	decoder := g.Shards()[0]
+	for ids := range results {
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				t.Errorf("duplicate ID %v", uint64(id))
+				return
+			}
+			seen[id] = struct{}{}
+			machineID := decoder.DecodeID(id).MachineID
+			if machineID>>g.ShardBits() != 5 {
+				t.Errorf("expected machine ID 5, got %v", machineID>>g.ShardBits())
+				return
+			}
+			shards[machineID&(1<<g.ShardBits()-1)] = struct{}{}
+		}
+	}
+	if len(shards) != 8 {
+		t.Errorf("expected IDs of 8 shards, got %v", len(shards))
+	}
+}
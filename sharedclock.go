@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrDuplicateMachineID is returned when the same machine ID is used for multiple generators of a set
+	ErrDuplicateMachineID = errors.New("duplicate machine ID")
+)
+
+// NewSharedClockSet creates a generator for each machine ID that all read the time from the same clock
+// Sharing the clock prevents skew between the generators, so their IDs can be compared by time
+// Each generator has its own state and is safe for concurrent use independently of the others
+// opts are applied to every generator after the layout and clock
+// Returns an error if a machine ID is used more than once or a generator cannot be created
+func NewSharedClockSet(clock func() time.Time, layout Layout, ids []uint64, opts ...Option) ([]*Generator, error) {
+	seen := make(map[uint64]struct{}, len(ids))
+	generators := make([]*Generator, 0, len(ids))
+	opts = append([]Option{withLayout(layout), withClock(clock)}, opts...)
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateMachineID, id)
+		}
+		seen[id] = struct{}{}
+		g, err := NewGenerator(id, opts...)
+		if err != nil {
+			return nil, err
+		}
+		generators = append(generators, g)
+	}
+	return generators, nil
+}
+
+// withLayout sets the layout of the generator
+func withLayout(layout Layout) Option {
+	return func(generator *Generator) {
+		generator.layout = layout
+	}
+}
+
+// withClock sets the time function of the generator to read the time from a clock
+func withClock(clock func() time.Time) Option {
+	return func(generator *Generator) {
+		generator.SetTimeFunc(func() uint64 {
+			return uint64(generator.ticks(clock()))
+		})
+	}
+}
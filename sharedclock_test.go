@@ -0,0 +1,72 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestNewSharedClockSet tests that the generators of a shared clock set read the same clock and generate unique IDs
+func TestNewSharedClockSet(t *testing.T) {
+	var mu sync.Mutex
+	now := time.UnixMilli(1656432460105)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	generators, err := NewSharedClockSet(clock, DefaultLayout(), []uint64{1, 2, 3}, WithEpoch(time.UnixMilli(1288834974657)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if len(generators) != 3 {
+		t.Errorf("expected 3 generators, got %v", len(generators))
+		return
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]ID, len(generators))
+	for i, g := range generators {
+		wg.Add(1)
+		go func(i int, g *Generator) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				id, err := g.NextID()
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				results[i] = append(results[i], id)
+			}
+		}(i, g)
+	}
+	wg.Wait()
+
+	seen := make(map[ID]struct{})
+	for i, ids := range results {
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				t.Errorf("duplicate id %v", id)
+				return
+			}
+			seen[id] = struct{}{}
+			decoded := generators[i].DecodeID(id)
+			if decoded.Timestamp != 367597485448 || decoded.MachineID != uint64(i+1) {
+				t.Errorf("expected timestamp 367597485448 and machine ID %v, got %v", i+1, decoded)
+				return
+			}
+		}
+	}
+}
+
+// TestNewSharedClockSet_Errors tests the errors of NewSharedClockSet
+func TestNewSharedClockSet_Errors(t *testing.T) {
+	if _, err := NewSharedClockSet(time.Now, DefaultLayout(), []uint64{1, 2, 1}); !errors.Is(err, ErrDuplicateMachineID) {
+		t.Errorf("expected ErrDuplicateMachineID, got %v", err)
+	}
+	if _, err := NewSharedClockSet(time.Now, DefaultLayout(), []uint64{1 << 10}); !errors.Is(err, ErrMachineIDTooLarge) {
+		t.Errorf("expected ErrMachineIDTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package snowflake
+
+import (
+	"fmt"
+	"math"
+)
+
+// WithSignedSafe guarantees that no generated ID exceeds math.MaxInt64, for databases and languages that only have
+// signed 64-bit integers, so Int64 never fails for the IDs of the generator
+// The fields of every layout add up to 63 bits and NewGenerator rejects layouts that do not, so the most significant
+// bit is only set by WithVersionBit(1). NewGenerator returns ErrIDOutOfRange when it is combined with WithVersionBit(1)
+func WithSignedSafe() Option {
+	return func(generator *Generator) {
+		generator.signedSafe = true
+	}
+}
+
+// Int64 returns the ID as an int64, e.g. for a database column of a signed 64-bit integer type
+// Returns ErrIDOutOfRange if the most significant bit is set, the ID would be negative as an int64
+func (id ID) Int64() (int64, error) {
+	if id > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %d", ErrIDOutOfRange, uint64(id))
+	}
+	return int64(id), nil
+}
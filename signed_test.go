@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestWithSignedSafe tests that WithSignedSafe rejects the options that can set the most significant bit
+func TestWithSignedSafe(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want error
+	}{
+		{name: "default layout"},
+		{name: "layout of 63 bits", opts: []Option{WithLayout(45, 8, 10)}},
+		{name: "version 0", opts: []Option{WithVersionBit(0)}},
+		{name: "version 1", opts: []Option{WithVersionBit(1)}, want: ErrIDOutOfRange},
+		{name: "layout of 64 bits", opts: []Option{WithLayout(42, 10, 12)}, want: ErrInvalidLayout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewGenerator(1, append(tt.opts, WithSignedSafe())...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+			id, err := generator.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if _, err = id.Int64(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestID_Int64 tests that Int64 converts IDs up to math.MaxInt64 and rejects larger IDs
+func TestID_Int64(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      ID
+		want    int64
+		wantErr error
+	}{
+		{name: "zero", id: 0, want: 0},
+		{name: "Twitter test vector", id: 1541815603606036480, want: 1541815603606036480},
+		{name: "max int64", id: math.MaxInt64, want: math.MaxInt64},
+		{name: "most significant bit set", id: 1 << 63, wantErr: ErrIDOutOfRange},
+		{name: "max uint64", id: math.MaxUint64, wantErr: ErrIDOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.id.Int64()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
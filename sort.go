@@ -0,0 +1,49 @@
+package snowflake
+
+import "sort"
+
+// Compare compares the ID with other as unsigned integers
+// Returns -1 if the ID is less than other, 1 if it is greater and 0 if they are equal, with the default field order
+// a lower ID is older
+func (id ID) Compare(other ID) int {
+	switch {
+	case id < other:
+		return -1
+	case id > other:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Sort sorts the IDs in ascending order, which is by time with the default field order
+func Sort(ids []ID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+}
+
+// Min returns the lowest of the IDs, or 0 without IDs
+func Min(ids ...ID) ID {
+	if len(ids) == 0 {
+		return 0
+	}
+	m := ids[0]
+	for _, id := range ids[1:] {
+		if id < m {
+			m = id
+		}
+	}
+	return m
+}
+
+// Max returns the highest of the IDs, or 0 without IDs
+func Max(ids ...ID) ID {
+	var m ID
+	for _, id := range ids {
+		if id > m {
+			m = id
+		}
+	}
+	return m
+}
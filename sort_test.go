@@ -0,0 +1,67 @@
+package snowflake
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestID_Compare tests that IDs are compared as unsigned integers
+func TestID_Compare(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    ID
+		other ID
+		want  int
+	}{
+		{name: "less", id: 1, other: 2, want: -1},
+		{name: "greater", id: 2, other: 1, want: 1},
+		{name: "equal", id: 2, other: 2, want: 0},
+		{name: "most significant bit set", id: 1 << 63, other: 1<<63 - 1, want: 1},
+		{name: "max", id: 0, other: 1<<64 - 1, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.Compare(tt.other); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSort tests that Sort sorts IDs in ascending unsigned order
+func TestSort(t *testing.T) {
+	ids := []ID{1 << 63, 5, 1<<64 - 1, 0, 1<<63 - 1, 5}
+	Sort(ids)
+	want := []ID{0, 5, 5, 1<<63 - 1, 1 << 63, 1<<64 - 1}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected %v, got %v", want, ids)
+	}
+	Sort(nil)
+}
+
+// TestMinMax tests the lowest and highest of IDs in unsigned order
+func TestMinMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []ID
+		wantMin ID
+		wantMax ID
+	}{
+		{name: "none", wantMin: 0, wantMax: 0},
+		{name: "one", ids: []ID{7}, wantMin: 7, wantMax: 7},
+		{name: "several", ids: []ID{3, 1, 2}, wantMin: 1, wantMax: 3},
+		{name: "most significant bit set", ids: []ID{1 << 63, 1<<63 - 1, 1<<64 - 1}, wantMin: 1<<63 - 1,
+			wantMax: 1<<64 - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Min(tt.ids...); got != tt.wantMin {
+				t.Errorf("expected %v, got %v", tt.wantMin, got)
+				return
+			}
+			if got := Max(tt.ids...); got != tt.wantMax {
+				t.Errorf("expected %v, got %v", tt.wantMax, got)
+			}
+		})
+	}
+}
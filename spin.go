@@ -0,0 +1,37 @@
+package snowflake
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+// WithSpinOnExhaustion makes NextID spin for at most maxWait of wall clock time when the sequence is exhausted,
+// retrying until the clock moves on, before it returns ErrSequenceExhausted
+// This is a middle ground between the immediate error of NextID and BlockingNextID, which waits as long as it takes.
+// The spin yields the processor with runtime.Gosched between retries, so keep maxWait in the order of the time unit
+// Zero or a negative maxWait disables spinning, which is the default. In strict mode NextID never spins
+func WithSpinOnExhaustion(maxWait time.Duration) Option {
+	return func(generator *Generator) {
+		generator.spinWait = maxWait
+	}
+}
+
+// spinNextID retries NextID after an exhausted sequence until it succeeds or the spin time is used up
+func (g *Generator) spinNextID() (ID, error) {
+	// nextID resets the flag, so it is set when the spinning is done
+	defer g.lastCallBlocked.Store(true)
+	start := time.Now()
+	if g.observer != nil {
+		defer func() {
+			g.observer.ClockWaited(time.Since(start))
+		}()
+	}
+	for {
+		runtime.Gosched()
+		id, err := g.nextID(g.machineID.Load(), 0)
+		if !errors.Is(err, ErrOutOfSequence) || time.Since(start) >= g.spinWait {
+			return id, err
+		}
+	}
+}
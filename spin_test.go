@@ -0,0 +1,66 @@
+package snowflake
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWithSpinOnExhaustion tests that NextID spins until the clock moves on within the spin time and returns
+// ErrSequenceExhausted otherwise
+func TestWithSpinOnExhaustion(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		advanceAt uint64
+		want      error
+	}{
+		{name: "clock advances", opts: []Option{WithSpinOnExhaustion(time.Second)}, advanceAt: 100},
+		{name: "clock stalls", opts: []Option{WithSpinOnExhaustion(5 * time.Millisecond)}, want: ErrSequenceExhausted},
+		{name: "default", advanceAt: 100, want: ErrSequenceExhausted},
+		{name: "disabled", opts: []Option{WithSpinOnExhaustion(0)}, advanceAt: 100, want: ErrSequenceExhausted},
+		{name: "strict", opts: []Option{WithSpinOnExhaustion(time.Second), WithStrict()}, advanceAt: 100,
+			want: ErrSequenceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// With 20 machine ID bits there are four sequence numbers per millisecond
+			opts := append([]Option{WithEpoch(time.UnixMilli(0)), WithMachineIDBits(20)}, tt.opts...)
+			generator, err := NewGenerator(378, opts...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			var calls atomic.Uint64
+			generator.SetTimeFunc(func() uint64 {
+				if tt.advanceAt > 0 && calls.Add(1) > tt.advanceAt {
+					return 367597485449
+				}
+				return 367597485448
+			})
+			for i := 0; i < 4; i++ {
+				if _, err = generator.NextID(); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+
+			id, err := generator.NextID()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got := generator.DecodeID(id).Timestamp; got != 367597485449 {
+				t.Errorf("expected %v, got %v", 367597485449, got)
+				return
+			}
+			if !generator.LastCallBlocked() {
+				t.Errorf("expected the call to be reported as blocked")
+			}
+		})
+	}
+}
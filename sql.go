@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrIDOutOfRange is returned when an ID does not fit in a signed 64-bit integer, such as a bigint database column
+	ErrIDOutOfRange = errors.New("ID does not fit in int64")
+)
+
+// Value returns the snowflake ID as an int64, which maps to a bigint column
+// It implements the driver.Valuer interface of database/sql
+// Returns ErrIDOutOfRange if the ID is larger than math.MaxInt64, instead of wrapping it to a negative number
+func (id ID) Value() (driver.Value, error) {
+	value, err := id.Int64()
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Scan reads the snowflake ID from an int64, or from a decimal string or []byte
+// It implements the sql.Scanner interface of database/sql, NULL scans as the zero ID
+// Returns ErrIDOutOfRange for a negative int64 and an error for an invalid decimal or an unsupported type
+func (id *ID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*id = 0
+	case int64:
+		if src < 0 {
+			return fmt.Errorf("%w: %d is negative", ErrIDOutOfRange, src)
+		}
+		*id = ID(src)
+	case []byte:
+		parsed, err := ParseIDFast(src)
+		if err != nil {
+			return err
+		}
+		*id = parsed
+	case string:
+		parsed, err := ParseID(src)
+		if err != nil {
+			return err
+		}
+		*id = parsed
+	default:
+		return fmt.Errorf("%w: unsupported database value %v of type %T", ErrInvalidID, src, src)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+var (
+	_ driver.Valuer = ID(0)
+	_ sql.Scanner   = (*ID)(nil)
+)
+
+// TestID_Value tests that Value returns an int64 and rejects IDs above math.MaxInt64
+func TestID_Value(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      ID
+		want    driver.Value
+		wantErr error
+	}{
+		{name: "zero", id: 0, want: int64(0)},
+		{name: "twitter", id: 1541815603606036480, want: int64(1541815603606036480)},
+		{name: "max int64", id: math.MaxInt64, want: int64(math.MaxInt64)},
+		{name: "above max int64", id: math.MaxInt64 + 1, wantErr: ErrIDOutOfRange},
+		{name: "max", id: math.MaxUint64, wantErr: ErrIDOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.id.Value()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestID_Scan tests that Scan accepts int64, []byte, string and NULL, and rejects everything else
+func TestID_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    ID
+		wantErr error
+	}{
+		{name: "null", src: nil, want: 0},
+		{name: "int64", src: int64(1541815603606036480), want: 1541815603606036480},
+		{name: "bytes", src: []byte("1541815603606036480"), want: 1541815603606036480},
+		{name: "string", src: "1541815603606036480", want: 1541815603606036480},
+		{name: "max string", src: "18446744073709551615", want: math.MaxUint64},
+		{name: "negative int64", src: int64(-1), wantErr: ErrIDOutOfRange},
+		{name: "overflowing string", src: "18446744073709551616", wantErr: ErrInvalidID},
+		{name: "invalid bytes", src: []byte("abc"), wantErr: ErrInvalidID},
+		{name: "float64", src: 1.5, wantErr: ErrInvalidID},
+		{name: "time", src: time.Time{}, wantErr: ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id ID = 1
+			err := id.Scan(tt.src)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			if err == nil && id != tt.want {
+				t.Errorf("expected %v, got %v", uint64(tt.want), uint64(id))
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrIncompatibleState is returned when a state is imported into a generator with a different layout or epoch
+	ErrIncompatibleState = errors.New("incompatible state")
+)
+
+// State is a snapshot of the position of a generator, used to carry it over to a reconfigured generator
+// Timestamp is the number of milliseconds since the epoch and Sequence the sequence of the last generated ID, both
+// are zero when the generator has not generated an ID yet
+type State struct {
+	Layout    Layout
+	Epoch     time.Time
+	Timestamp uint64
+	Sequence  uint64
+}
+
+// ExportState returns the state of the generator, for example to import it into a new generator on a configuration
+// reload
+// IDs that are generated concurrently may or may not be included, stop generating IDs with the old generator before
+// exporting its state to make sure the new generator continues after all of them
+func (g *Generator) ExportState() State {
+	currentID := g.currentID.Load()
+	return State{
+		Layout:    g.layout,
+		Epoch:     g.Epoch(),
+		Timestamp: currentID >> g.stateShift,
+		Sequence:  currentID & g.sequenceMask,
+	}
+}
+
+// ImportState continues the generator after the state of another generator, so it does not reissue the IDs that the
+// other generator generated with the same machine ID
+// The state is only imported when it is ahead of the generator, a generator that is already further never goes back.
+// The sequences of NextIDAt for times before the first generated ID are not part of the state, after an import
+// NextIDAt returns ErrTimeAlreadyUsed for all times before the last generated ID
+// Returns ErrIncompatibleState wrapped with the difference if the layout or the epoch of the state differs from the
+// generator, IDs of different layouts or epochs cannot be compared, and ErrTimestampOverflow or ErrSequenceTooLarge
+// if the position does not fit in the layout
+func (g *Generator) ImportState(state State) error {
+	if state.Layout != g.layout {
+		return fmt.Errorf("%w: layout %+v differs from %+v", ErrIncompatibleState, state.Layout, g.layout)
+	}
+	if g.ticks(state.Epoch) != g.epoch {
+		return fmt.Errorf("%w: epoch %v differs from %v", ErrIncompatibleState, state.Epoch.UTC(), g.Epoch().UTC())
+	}
+	if state.Timestamp > g.layout.timestampMask() {
+		return fmt.Errorf("%w: %d does not fit in %d", ErrTimestampOverflow, state.Timestamp, g.layout.timestampMask())
+	}
+	if state.Sequence > g.sequenceMask {
+		return fmt.Errorf("%w: %d does not fit in %d", ErrSequenceTooLarge, state.Sequence, g.sequenceMask)
+	}
+	imported := state.Timestamp<<g.stateShift | state.Sequence
+	// The other generator may have generated IDs in any millisecond before the state, so NextIDAt no longer has a
+	// sequence of its own for earlier times
+	g.lowerFloor(0)
+	for {
+		currentID := g.currentID.Load()
+		if currentID >= imported || g.currentID.CompareAndSwap(currentID, imported) {
+			return nil
+		}
+	}
+}
+
+// Reset clears the timestamp and sequence of the last generated ID, also of NextIDAt, so the generator starts like a
+// new generator with the same machine ID, epoch, layout and options, e.g. for deterministic tests with a fixed time
+// function or to reuse a pooled generator
+// It is safe for concurrent use, IDs that are generated concurrently are generated either before or after the reset.
+// The history, statistics and a sequence strategy are not reset
+// Resetting a generator that already generated IDs for the current time causes duplicate IDs, because it generates
+// the sequence of that millisecond again. This also voids the guarantee of WithMonotonic
+func (g *Generator) Reset() {
+	g.strategyMu.Lock()
+	defer g.strategyMu.Unlock()
+	g.currentID.Store(0)
+	g.floor.Store(0)
+	g.pastMu.Lock()
+	g.past = nil
+	g.pastMu.Unlock()
+	g.lastCallBlocked.Store(false)
+}
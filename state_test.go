@@ -0,0 +1,169 @@
+package snowflake
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestGenerator_ImportState tests that a reconfigured generator continues after the exported state
+func TestGenerator_ImportState(t *testing.T) {
+	now := uint64(367597485448)
+	timeFunc := func() uint64 {
+		return now
+	}
+	old, err := NewGenerator(5, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	old.SetTimeFunc(timeFunc)
+	for i := 0; i < 3; i++ {
+		if _, err := old.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	state := old.ExportState()
+	if want := (State{Layout: DefaultLayout(), Epoch: time.UnixMilli(0), Timestamp: now, Sequence: 2}); state != want {
+		t.Errorf("expected %+v, got %+v", want, state)
+		return
+	}
+
+	// The new configuration enables strict mode, which does not change the layout
+	reloaded, err := NewGenerator(5, WithEpoch(time.UnixMilli(0)), WithStrict())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	reloaded.SetTimeFunc(timeFunc)
+	if err := reloaded.ImportState(state); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	id, err := reloaded.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	verifyRoundTrip(t, reloaded, id, now, 3)
+
+	// An older state does not move the generator back
+	older := State{Layout: DefaultLayout(), Epoch: time.UnixMilli(0), Timestamp: now - 1}
+	if err := reloaded.ImportState(older); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := reloaded.ExportState(); got.Timestamp != now || got.Sequence != 3 {
+		t.Errorf("expected timestamp %v and sequence 3, got %+v", now, got)
+	}
+}
+
+// TestGenerator_ImportState_Errors tests that incompatible or invalid states are rejected
+func TestGenerator_ImportState_Errors(t *testing.T) {
+	generator, err := NewGenerator(5, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	tests := []struct {
+		name     string
+		state    State
+		expected error
+	}{
+		{
+			name:     "layout",
+			state:    State{Layout: Layout{MachineIDBits: 12}, Epoch: time.UnixMilli(0)},
+			expected: ErrIncompatibleState,
+		},
+		{
+			name:     "epoch",
+			state:    State{Layout: DefaultLayout(), Epoch: time.UnixMilli(1)},
+			expected: ErrIncompatibleState,
+		},
+		{
+			name:     "timestamp",
+			state:    State{Layout: DefaultLayout(), Epoch: time.UnixMilli(0), Timestamp: 1 << 42},
+			expected: ErrTimestampOverflow,
+		},
+		{
+			name:     "sequence",
+			state:    State{Layout: DefaultLayout(), Epoch: time.UnixMilli(0), Sequence: 1 << 12},
+			expected: ErrSequenceTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := generator.ImportState(tt.state); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+	if got := generator.ExportState(); got.Timestamp != 0 || got.Sequence != 0 {
+		t.Errorf("expected the state to be unchanged, got %+v", got)
+	}
+}
+
+// TestGenerator_Reset tests that a reset generator generates the same IDs again for the same time
+func TestGenerator_Reset(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	first, err := generator.NextIDs(10)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.Reset()
+	if last := generator.LastID(); last != 0 {
+		t.Errorf("expected 0, got %v", uint64(last))
+		return
+	}
+	if got := generator.Remaining(); got != 4096 {
+		t.Errorf("expected 4096, got %v", got)
+		return
+	}
+	second, err := generator.NextIDs(10)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expected %v, got %v", first, second)
+		return
+	}
+	if got := generator.MachineID(); got != 378 {
+		t.Errorf("expected 378, got %v", got)
+	}
+}
+
+// TestGenerator_Reset_Concurrent tests that Reset can be called while IDs are generated
+func TestGenerator_Reset_Concurrent(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_, _ = generator.NextID()
+				if j%100 == 0 {
+					generator.Reset()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
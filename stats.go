@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds the machine ID of the generator, the number of machine ID collisions and the time spent generating IDs,
+// the time is recorded when WithGenerationTiming is enabled
+type Stats struct {
+	// MachineID is the machine ID of the generator
+	MachineID uint64
+	// MachineIDCollisions is the number of collisions that RecoverMachineIDCollision recovered from
+	MachineIDCollisions uint64
+	// Calls is the number of times the state of the generator was reserved
+	Calls uint64
+	// TotalTime is the cumulative time spent reading the clock and updating the state
+	TotalTime time.Duration
+	// MaxTime is the longest time a single call spent reading the clock and updating the state
+	MaxTime time.Duration
+}
+
+// generationTiming records the time spent in the critical section of the generator
+type generationTiming struct {
+	calls atomic.Uint64
+	total atomic.Int64
+	max   atomic.Int64
+}
+
+// record records a call that took d
+func (t *generationTiming) record(d time.Duration) {
+	t.calls.Add(1)
+	t.total.Add(int64(d))
+	for {
+		max := t.max.Load()
+		if int64(d) <= max || t.max.CompareAndSwap(max, int64(d)) {
+			return
+		}
+	}
+}
+
+// WithGenerationTiming enables recording the time spent reading the clock and updating the state of the generator
+// A slow time function or contention on the state shows up as a high maximum time, use Stats to read the timing
+// The timing uses the monotonic clock and costs two clock reads per ID, so it is disabled by default
+func WithGenerationTiming() Option {
+	return func(generator *Generator) {
+		generator.timing = &generationTiming{}
+	}
+}
+
+// Stats returns the machine ID, the number of machine ID collisions and the time spent generating IDs
+// Returns zero times if WithGenerationTiming is not enabled
+func (g *Generator) Stats() Stats {
+	stats := Stats{
+		MachineID:           g.machineID.Load(),
+		MachineIDCollisions: g.collisions.Load(),
+	}
+	if g.timing != nil {
+		stats.Calls = g.timing.calls.Load()
+		stats.TotalTime = time.Duration(g.timing.total.Load())
+		stats.MaxTime = time.Duration(g.timing.max.Load())
+	}
+	return stats
+}
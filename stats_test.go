@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWithGenerationTiming tests that the time spent in a slow time function is recorded
+func TestWithGenerationTiming(t *testing.T) {
+	generator, err := NewGenerator(378, WithGenerationTiming())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		time.Sleep(2 * time.Millisecond)
+		return defaultTimeFunc()
+	})
+	for i := 0; i < 3; i++ {
+		if _, err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	stats := generator.Stats()
+	if stats.Calls != 3 {
+		t.Errorf("expected 3 calls, got %v", stats.Calls)
+	}
+	if stats.MaxTime < 2*time.Millisecond || stats.TotalTime < 6*time.Millisecond {
+		t.Errorf("expected the time of the time function to be recorded, got %+v", stats)
+	}
+	if stats.MaxTime > stats.TotalTime {
+		t.Errorf("expected the maximum time to be at most the total time, got %+v", stats)
+	}
+}
+
+// TestGenerator_Stats_Disabled tests that Stats returns only the machine ID without WithGenerationTiming
+func TestGenerator_Stats_Disabled(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if stats := generator.Stats(); stats != (Stats{MachineID: 378}) {
+		t.Errorf("expected only the machine ID, got %+v", stats)
+	}
+}
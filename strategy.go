@@ -0,0 +1,117 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSequenceStrategyBatch is returned by calls that reserve several sequence numbers at once when a sequence
+	// strategy is set, because a strategy hands out one sequence number at a time
+	ErrSequenceStrategyBatch = errors.New("sequence strategy does not support reserving several sequence numbers")
+)
+
+// SequenceStrategy allocates the sequence numbers of a generator set with WithSequenceStrategy
+// The generator calls Next with the milliseconds since the epoch of the ID it is about to generate. Next returns a
+// sequence number for that timestamp and true, or false when the timestamp has no sequence numbers left
+// The contract of a strategy is:
+//   - Next is called by one goroutine at a time and with timestamps that never decrease
+//   - the sequence number must fit in the sequence bits of the layout, otherwise the generator returns
+//     ErrSequenceTooLarge
+//   - the sequence number must not have been returned before for the same timestamp, the generator does not check
+//     this, uniqueness of the IDs is the responsibility of the strategy
+//   - once Next returns false for a timestamp it must keep returning false for it, the generator then moves on to the
+//     next millisecond with drift, returns ErrOutOfSequence or BlockingNextID waits for the clock
+//
+// IDs within one millisecond are ordered by the sequence numbers the strategy returns, which need not increase
+type SequenceStrategy interface {
+	Next(timestamp uint64) (sequence uint64, ok bool)
+}
+
+// IncrementSequenceStrategy is the reference implementation of SequenceStrategy, it allocates sequence numbers the
+// way a generator without a strategy does: starting at zero in every millisecond and incrementing until the sequence
+// bits are exhausted
+// It is safe for concurrent use, but sharing it between generators shares the sequence numbers too
+type IncrementSequenceStrategy struct {
+	mu           sync.Mutex
+	sequenceMask uint64
+	timestamp    uint64
+	sequence     uint64
+	started      bool
+}
+
+// NewIncrementSequenceStrategy creates an increment-and-wrap strategy for the given number of sequence bits
+func NewIncrementSequenceStrategy(sequenceBits uint64) *IncrementSequenceStrategy {
+	return &IncrementSequenceStrategy{sequenceMask: 1<<sequenceBits - 1}
+}
+
+// Next returns the next sequence number of the timestamp, or false when the sequence bits are exhausted
+func (s *IncrementSequenceStrategy) Next(timestamp uint64) (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started || timestamp != s.timestamp {
+		s.started = true
+		s.timestamp = timestamp
+		s.sequence = 0
+		return 0, true
+	}
+	if s.sequence == s.sequenceMask {
+		return 0, false
+	}
+	s.sequence++
+	return s.sequence, true
+}
+
+// WithSequenceStrategy replaces the allocation of sequence numbers by the given strategy, see SequenceStrategy for the
+// contract a strategy must follow
+// The strategy is only used by calls that generate one ID, calls that reserve several sequence numbers at once such
+// as NextIDPair, ClaimMillisecond, ReserveBlock and NextIDWithPayload return ErrSequenceStrategyBatch. NextIDAt uses
+// the strategy for times at or after the last generated ID. WithInitialSequence and WithMonotonicityAssertion have
+// no effect, because the strategy decides the sequence numbers
+func WithSequenceStrategy(strategy SequenceStrategy) Option {
+	return func(generator *Generator) {
+		generator.strategy = strategy
+	}
+}
+
+// reserveFromStrategy reserves one sequence number from the sequence strategy and returns the state it replaced and
+// its state
+// The timestamp never goes back, when the clock is behind the last generated ID the timestamp of that ID is used
+func (g *Generator) reserveFromStrategy(now uint64) (previous uint64, state uint64, err error) {
+	g.strategyMu.Lock()
+	defer g.strategyMu.Unlock()
+
+	previous = g.currentID.Load()
+	timestamp := now
+	if lastTime := previous >> g.stateShift; lastTime > now {
+		if g.strict {
+			return 0, 0, fmt.Errorf("%w: %v behind the last generated ID", ErrClockMovedBackwards,
+				time.Duration(lastTime-now)*g.unit)
+		}
+		timestamp = lastTime
+	}
+	for {
+		sequence, ok := g.strategy.Next(timestamp)
+		if ok {
+			if sequence > g.sequenceMask {
+				return 0, 0, ErrSequenceTooLarge
+			}
+			state = timestamp<<g.stateShift | sequence
+			if previous == 0 {
+				g.lowerFloor(state)
+			}
+			g.currentID.Store(state)
+			return previous, state, nil
+		}
+		if g.strict || timestamp-now >= g.driftWindow() {
+			g.sequenceExhausted()
+			return 0, 0, ErrOutOfSequence
+		}
+		if timestamp > g.layout.timestampMask()-g.step {
+			return 0, 0, ErrTimestampOverflow
+		}
+		timestamp += g.step
+	}
+}
@@ -0,0 +1,156 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// evenSequenceStrategy is a strategy that only allocates even sequence numbers
+type evenSequenceStrategy struct {
+	inner *IncrementSequenceStrategy
+}
+
+// Next returns twice the sequence number of the inner strategy
+func (s evenSequenceStrategy) Next(timestamp uint64) (uint64, bool) {
+	sequence, ok := s.inner.Next(timestamp)
+	return sequence * 2, ok
+}
+
+// fixedSequenceStrategy is a strategy that always returns the same sequence number
+type fixedSequenceStrategy uint64
+
+// Next returns the sequence number of the strategy
+func (s fixedSequenceStrategy) Next(uint64) (uint64, bool) {
+	return uint64(s), true
+}
+
+// TestIncrementSequenceStrategy tests that the reference strategy generates the same IDs as the default allocation
+func TestIncrementSequenceStrategy(t *testing.T) {
+	timestamps := []uint64{1656432460105, 1656432460105, 1656432460105, 1656432460106, 1656432460106, 1656432460110}
+	newGenerator := func(opts ...Option) *Generator {
+		i := 0
+		generator, err := NewGenerator(1, append(opts, WithMachineIDBits(20), WithEpoch(time.UnixMilli(0)))...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		generator.SetTimeFunc(func() uint64 {
+			timestamp := timestamps[i]
+			i++
+			return timestamp
+		})
+		return generator
+	}
+	reference := newGenerator()
+	generator := newGenerator(WithSequenceStrategy(NewIncrementSequenceStrategy(2)))
+	for range timestamps {
+		want, err := reference.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		got, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if got != want {
+			t.Errorf("expected %v, got %v", want, got)
+			return
+		}
+	}
+}
+
+// TestWithSequenceStrategy tests that the sequence numbers come from the strategy and that an exhausted strategy
+// moves on to the next millisecond with drift
+func TestWithSequenceStrategy(t *testing.T) {
+	generator, err := NewGenerator(1, WithMachineIDBits(19), WithEpoch(time.UnixMilli(0)), WithDriftNoWait(time.Second),
+		WithSequenceStrategy(evenSequenceStrategy{inner: NewIncrementSequenceStrategy(1)}))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 1656432460105
+	})
+	tests := []struct {
+		timestamp uint64
+		sequence  uint64
+	}{
+		{timestamp: 1656432460105, sequence: 0},
+		{timestamp: 1656432460105, sequence: 2},
+		{timestamp: 1656432460106, sequence: 0},
+		{timestamp: 1656432460106, sequence: 2},
+		{timestamp: 1656432460107, sequence: 0},
+	}
+	for _, tt := range tests {
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, tt.timestamp, tt.sequence)
+	}
+}
+
+// TestWithSequenceStrategy_BlockingNextID tests that BlockingNextID waits for the clock when the strategy is exhausted
+func TestWithSequenceStrategy_BlockingNextID(t *testing.T) {
+	clock := NewManualClock(time.UnixMilli(1656432460105))
+	generator, err := NewGenerator(1, WithMachineIDBits(21), WithEpoch(time.UnixMilli(0)), WithClockInterface(clock),
+		WithSequenceStrategy(NewIncrementSequenceStrategy(1)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := generator.BlockingNextID(context.Background()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if !generator.LastCallBlocked() {
+		t.Errorf("expected the last call to block")
+	}
+	if want := time.UnixMilli(1656432460106); !generator.LastTimestamp().Equal(want) {
+		t.Errorf("expected %v, got %v", want, generator.LastTimestamp())
+	}
+}
+
+// TestWithSequenceStrategy_Errors tests the errors of a generator with a sequence strategy
+func TestWithSequenceStrategy_Errors(t *testing.T) {
+	newGenerator := func(strategy SequenceStrategy, opts ...Option) *Generator {
+		generator, err := NewGenerator(1, append(opts, WithSequenceStrategy(strategy))...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		generator.SetTimeFunc(func() uint64 {
+			return 1709247600000 + 100
+		})
+		return generator
+	}
+
+	generator := newGenerator(NewIncrementSequenceStrategy(12))
+	if _, _, err := generator.NextIDPair(); !errors.Is(err, ErrSequenceStrategyBatch) {
+		t.Errorf("expected %v, got %v", ErrSequenceStrategyBatch, err)
+	}
+	if _, err := generator.ClaimMillisecond(); !errors.Is(err, ErrSequenceStrategyBatch) {
+		t.Errorf("expected %v, got %v", ErrSequenceStrategyBatch, err)
+	}
+	if _, err := generator.NextIDWithPayload(1); !errors.Is(err, ErrSequenceStrategyBatch) {
+		t.Errorf("expected %v, got %v", ErrSequenceStrategyBatch, err)
+	}
+
+	generator = newGenerator(fixedSequenceStrategy(1 << 12))
+	if _, err := generator.NextID(); !errors.Is(err, ErrSequenceTooLarge) {
+		t.Errorf("expected %v, got %v", ErrSequenceTooLarge, err)
+	}
+
+	generator = newGenerator(NewIncrementSequenceStrategy(0), WithStrict())
+	if _, err := generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if _, err := generator.NextID(); !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("expected %v, got %v", ErrOutOfSequence, err)
+	}
+}
@@ -0,0 +1,37 @@
+package snowflake
+
+import "context"
+
+// StreamN returns a channel that yields n IDs generated with BlockingNextID and is closed after the last one
+// The channel is closed early when the context is canceled or BlockingNextID returns an error, so a consumer that
+// stops reading must cancel the context to stop the goroutine that generates the IDs, a nil context is never
+// canceled
+// The error channel yields the error of BlockingNextID that stopped the stream, such as ErrClockMovedBackwards in
+// strict mode, and is closed together with the ID channel. It is buffered, so it can be read after the ID channel
+// is drained. Canceling the context is not an error and closes both channels without one
+func (g *Generator) StreamN(ctx context.Context, n int) (<-chan ID, <-chan error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ids := make(chan ID)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(ids)
+		for i := 0; i < n; i++ {
+			id, err := g.BlockingNextID(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ids, errs
+}
@@ -0,0 +1,36 @@
+//go:build go1.23
+
+package snowflake
+
+import (
+	"context"
+	"iter"
+)
+
+// Stream returns an iterator that yields IDs generated with BlockingNextID until the context is canceled, for use
+// with range over func: for id, err := range generator.Stream(ctx)
+// When the context is canceled the iterator yields the error of the context with a zero ID and stops, and when
+// BlockingNextID returns an error, such as ErrClockMovedBackwards in strict mode, it yields that error and stops. A
+// nil context is never canceled, the loop then only ends with break or an error
+// The IDs are generated on the goroutine of the loop, so breaking out of the loop stops the iteration without
+// leaving a goroutine behind, unlike StreamN
+func (g *Generator) Stream(ctx context.Context) iter.Seq2[ID, error] {
+	return func(yield func(ID, error) bool) {
+		for {
+			if ctx != nil {
+				if err := ctx.Err(); err != nil {
+					yield(0, err)
+					return
+				}
+			}
+			id, err := g.BlockingNextID(ctx)
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
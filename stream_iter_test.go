@@ -0,0 +1,107 @@
+//go:build go1.23
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestGenerator_Stream tests that Stream yields consecutive IDs and that breaking out of the loop stops it
+func TestGenerator_Stream(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	goroutines := runtime.NumGoroutine()
+	var sequence uint64
+	for id, err := range generator.Stream(context.Background()) {
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		verifyRoundTrip(t, generator, id, 367597485448, sequence)
+		sequence++
+		if sequence == 100 {
+			break
+		}
+	}
+	if sequence != 100 {
+		t.Errorf("expected 100 IDs, got %v", sequence)
+	}
+	if got := runtime.NumGoroutine(); got > goroutines {
+		t.Errorf("expected at most %v goroutines, got %v", goroutines, got)
+	}
+}
+
+// TestGenerator_Stream_Canceled tests that Stream yields the error of the context when it is canceled and stops
+func TestGenerator_Stream_Canceled(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	var last error
+	for id, err := range generator.Stream(ctx) {
+		if err != nil {
+			if id != 0 {
+				t.Errorf("expected 0, got %v", uint64(id))
+			}
+			last = err
+			continue
+		}
+		count++
+		if count == 10 {
+			cancel()
+		}
+	}
+	if count != 10 {
+		t.Errorf("expected 10 IDs, got %v", count)
+	}
+	if !errors.Is(last, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, last)
+	}
+}
+
+// TestGenerator_Stream_Error tests that Stream yields the error of BlockingNextID and stops
+func TestGenerator_Stream_Error(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithStrict())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	// The clock moves backwards after the second ID
+	times := []uint64{367597485448, 367597485449, 367597485400}
+	calls := 0
+	generator.SetTimeFunc(func() uint64 {
+		now := times[calls]
+		calls++
+		return now
+	})
+	count := 0
+	var errs []error
+	for _, err := range generator.Stream(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 IDs, got %v", count)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrClockMovedBackwards) {
+		t.Errorf("expected %v, got %v", ErrClockMovedBackwards, errs)
+	}
+}
@@ -0,0 +1,105 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestGenerator_StreamN tests that StreamN yields exactly n IDs and then closes the channel
+func TestGenerator_StreamN(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	var sequence uint64
+	for _, n := range []int{0, 1, 100} {
+		count := 0
+		// A nil context is never canceled
+		ctx := context.Background()
+		if n == 1 {
+			ctx = nil
+		}
+		ids, errs := generator.StreamN(ctx, n)
+		for id := range ids {
+			verifyRoundTrip(t, generator, id, 367597485448, sequence)
+			sequence++
+			count++
+		}
+		if count != n {
+			t.Errorf("expected %v IDs, got %v", n, count)
+		}
+		if err, ok := <-errs; ok {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
+
+// TestGenerator_StreamN_Canceled tests that the goroutine of StreamN stops when the consumer stops reading and cancels
+// the context
+func TestGenerator_StreamN_Canceled(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	goroutines := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	ids, errs := generator.StreamN(ctx, 1000)
+	<-ids
+	cancel()
+	for range ids {
+	}
+	if err, ok := <-errs; ok {
+		t.Errorf("expected no error after cancel, got %v", err)
+	}
+
+	// The channel is closed by the goroutine, which exits right after
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > goroutines {
+		t.Errorf("expected %v goroutines, got %v", goroutines, got)
+	}
+}
+
+// TestGenerator_StreamN_Error tests that a clock rollback surfaces on the error channel and stops the stream, also
+// with a nil context
+func TestGenerator_StreamN_Error(t *testing.T) {
+	for _, ctx := range []context.Context{context.Background(), nil} {
+		generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithStrict())
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		// The clock moves backwards after the second ID
+		times := []uint64{367597485448, 367597485449, 367597485400}
+		calls := 0
+		generator.SetTimeFunc(func() uint64 {
+			now := times[calls]
+			calls++
+			return now
+		})
+		ids, errs := generator.StreamN(ctx, 10)
+		count := 0
+		for range ids {
+			count++
+		}
+		if count != 2 {
+			t.Errorf("expected 2 IDs, got %v", count)
+		}
+		if err := <-errs; !errors.Is(err, ErrClockMovedBackwards) {
+			t.Errorf("expected %v, got %v", ErrClockMovedBackwards, err)
+		}
+		if _, ok := <-errs; ok {
+			t.Errorf("expected the error channel to be closed")
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSequenceReset is returned when the sequence of an ID does not increase within the same timestamp
+	ErrSequenceReset = errors.New("sequence reset")
+)
+
+// StreamValidator checks a stream of IDs for sequence resets, which indicate that two generators share a machine ID
+// IDs must be checked in the order they were generated, the last ID is tracked per machine ID
+// A StreamValidator is not safe for concurrent use
+type StreamValidator struct {
+	layout Layout
+	last   map[uint64]DecodedID
+}
+
+// NewStreamValidator creates a stream validator for IDs with the given layout
+func NewStreamValidator(layout Layout) *StreamValidator {
+	return &StreamValidator{layout: layout, last: make(map[uint64]DecodedID)}
+}
+
+// Check checks the next ID of the stream
+// Returns ErrSequenceReset wrapped with the offending values when the ID has the same machine ID and timestamp as the
+// previous ID of that machine ID, but a sequence that is not larger
+func (v *StreamValidator) Check(id ID) error {
+	decoded := DecodeID(id, v.layout)
+	last, ok := v.last[decoded.MachineID]
+	v.last[decoded.MachineID] = decoded
+	if ok && last.Timestamp == decoded.Timestamp && decoded.Sequence <= last.Sequence {
+		return fmt.Errorf("%w: machine ID %d, timestamp %d, sequence %d after %d", ErrSequenceReset,
+			decoded.MachineID, decoded.Timestamp, decoded.Sequence, last.Sequence)
+	}
+	return nil
+}
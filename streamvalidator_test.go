@@ -0,0 +1,48 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestStreamValidator tests that the StreamValidator detects sequence resets per machine ID
+func TestStreamValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []ID
+		wantErr error
+	}{
+		{
+			name: "Test StreamValidator with increasing sequences",
+			ids:  []ID{5<<22 | 1<<12 | 0, 5<<22 | 1<<12 | 1, 6<<22 | 1<<12 | 0},
+		},
+		{
+			name: "Test StreamValidator with interleaved machine IDs",
+			ids:  []ID{5<<22 | 1<<12 | 3, 5<<22 | 2<<12 | 0, 5<<22 | 1<<12 | 4},
+		},
+		{
+			name:    "Test StreamValidator with a repeated sequence",
+			ids:     []ID{5<<22 | 1<<12 | 3, 5<<22 | 1<<12 | 3},
+			wantErr: ErrSequenceReset,
+		},
+		{
+			name:    "Test StreamValidator with a dropping sequence",
+			ids:     []ID{5<<22 | 1<<12 | 3, 5<<22 | 1<<12 | 4, 5<<22 | 1<<12 | 0},
+			wantErr: ErrSequenceReset,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewStreamValidator(DefaultLayout())
+			var err error
+			for _, id := range tt.ids {
+				if err = validator.Check(id); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package snowflake
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidTextDump is returned when a text dump has an invalid header or line
+	ErrInvalidTextDump = errors.New("invalid text dump")
+)
+
+// textDumpHeader is the format of the first line of a text dump, which records the layout of the IDs
+const textDumpHeader = "# snowflake ids version-bit=%t machine-id-bits=%d shard-bits=%d nonce-bits=%d order=%d single-node=%t"
+
+// textDumpTimestampBits is the format of the custom timestamp bits at the end of the header, it is left out for the
+// default timestamp bits, so dumps written before it was added are read with the default
+const textDumpTimestampBits = " timestamp-bits=%d"
+
+// formatTextDumpHeader returns the header line of a text dump of IDs with the layout, without the newline
+func formatTextDumpHeader(layout Layout) string {
+	header := fmt.Sprintf(textDumpHeader, layout.VersionBit, layout.MachineIDBits, layout.ShardBits, layout.NonceBits,
+		layout.Order, layout.SingleNode)
+	if layout.TimestampBits != 0 {
+		header += fmt.Sprintf(textDumpTimestampBits, layout.TimestampBits)
+	}
+	return header
+}
+
+// WriteIDsText writes the IDs as decimal numbers, one per line, after a header line that records the layout
+// Unless preserveOrder is set the IDs are written in ascending order, which changes their order but places IDs with
+// the same leading digits on consecutive lines, so the dump compresses well with gzip. The slice is not modified.
+// Returns the error of the layout if it is invalid
+func WriteIDsText(w io.Writer, ids []ID, layout Layout, preserveOrder bool) error {
+	if err := layout.validate(); err != nil {
+		return err
+	}
+	if !preserveOrder {
+		ids = append([]ID(nil), ids...)
+		sort.Slice(ids, func(i, j int) bool {
+			return ids[i] < ids[j]
+		})
+	}
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(formatTextDumpHeader(layout) + "\n"); err != nil {
+		return err
+	}
+	var buf [20]byte
+	for _, id := range ids {
+		line := append(strconv.AppendUint(buf[:0], uint64(id), 10), '\n')
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadIDsText reads IDs written with WriteIDsText and returns them with the layout recorded in the header
+// Returns ErrInvalidTextDump wrapped with the line number if the header or a line is invalid, or the error of the
+// layout if the recorded layout is invalid
+func ReadIDsText(r io.Reader) ([]ID, Layout, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, Layout{}, err
+		}
+		return nil, Layout{}, fmt.Errorf("%w: missing header", ErrInvalidTextDump)
+	}
+	var layout Layout
+	header := scanner.Text()
+	_, err := fmt.Sscanf(header, textDumpHeader, &layout.VersionBit, &layout.MachineIDBits, &layout.ShardBits,
+		&layout.NonceBits, &layout.Order, &layout.SingleNode)
+	if i := strings.Index(header, " timestamp-bits="); err == nil && i >= 0 {
+		_, err = fmt.Sscanf(header[i:], textDumpTimestampBits, &layout.TimestampBits)
+	}
+	// Sscanf ignores trailing input, so also require the header to be exactly as WriteIDsText writes it
+	if err != nil || header != formatTextDumpHeader(layout) {
+		return nil, Layout{}, fmt.Errorf("%w: invalid header %q", ErrInvalidTextDump, header)
+	}
+	if err := layout.validate(); err != nil {
+		return nil, Layout{}, err
+	}
+	var ids []ID
+	for line := 2; scanner.Scan(); line++ {
+		id, err := strconv.ParseUint(scanner.Text(), 10, 64)
+		if err != nil {
+			return nil, Layout{}, fmt.Errorf("%w: invalid ID on line %d", ErrInvalidTextDump, line)
+		}
+		ids = append(ids, ID(id))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, Layout{}, err
+	}
+	return ids, layout, nil
+}
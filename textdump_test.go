@@ -0,0 +1,101 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestWriteIDsText tests that IDs written with WriteIDsText are read back with their layout
+func TestWriteIDsText(t *testing.T) {
+	ids := []ID{1541815603606036481, 7, 1541815603606036480}
+	tests := []struct {
+		name          string
+		layout        Layout
+		preserveOrder bool
+		want          []ID
+		text          string
+	}{
+		{
+			name:   "sorted",
+			layout: DefaultLayout(),
+			want:   []ID{7, 1541815603606036480, 1541815603606036481},
+			text: "# snowflake ids version-bit=false machine-id-bits=10 shard-bits=0 nonce-bits=0 order=0 single-node=false\n" +
+				"7\n1541815603606036480\n1541815603606036481\n",
+		},
+		{
+			name:          "preserve order",
+			layout:        Layout{VersionBit: true, MachineIDBits: 5, ShardBits: 5, NonceBits: 2, Order: OrderSequenceTimestampMachine},
+			preserveOrder: true,
+			want:          ids,
+			text: "# snowflake ids version-bit=true machine-id-bits=5 shard-bits=5 nonce-bits=2 order=1 single-node=false\n" +
+				"1541815603606036481\n7\n1541815603606036480\n",
+		},
+		{
+			name:          "timestamp bits",
+			layout:        Layout{TimestampBits: 45, MachineIDBits: 10, Order: OrderTimestampSequenceMachine},
+			preserveOrder: true,
+			want:          ids,
+			text: "# snowflake ids version-bit=false machine-id-bits=10 shard-bits=0 nonce-bits=0 order=2 single-node=false" +
+				" timestamp-bits=45\n1541815603606036481\n7\n1541815603606036480\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteIDsText(&buf, ids, tt.layout, tt.preserveOrder); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if buf.String() != tt.text {
+				t.Errorf("expected %q, got %q", tt.text, buf.String())
+				return
+			}
+			got, layout, err := ReadIDsText(&buf)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || layout != tt.layout {
+				t.Errorf("expected %v and %v, got %v and %v", tt.want, tt.layout, got, layout)
+			}
+		})
+	}
+	if ids[0] != 1541815603606036481 {
+		t.Errorf("expected the slice not to be modified, got %v", ids)
+	}
+	if err := WriteIDsText(&bytes.Buffer{}, ids, Layout{}, false); !errors.Is(err, ErrMachineBitsTooSmall) {
+		t.Errorf("expected ErrMachineBitsTooSmall, got %v", err)
+	}
+}
+
+// TestReadIDsText_Errors tests that ReadIDsText rejects invalid dumps
+func TestReadIDsText_Errors(t *testing.T) {
+	header := "# snowflake ids version-bit=false machine-id-bits=10 shard-bits=0 nonce-bits=0 order=0 single-node=false\n"
+	tests := []struct {
+		name string
+		text string
+		want error
+	}{
+		{name: "empty", text: "", want: ErrInvalidTextDump},
+		{name: "missing header", text: "7\n", want: ErrInvalidTextDump},
+		{name: "trailing header", text: strings.TrimSuffix(header, "\n") + " extra\n7\n", want: ErrInvalidTextDump},
+		{name: "invalid ID", text: header + "7\nnot an id\n", want: ErrInvalidTextDump},
+		{name: "default timestamp bits", text: strings.TrimSuffix(header, "\n") + " timestamp-bits=0\n7\n",
+			want: ErrInvalidTextDump},
+		{name: "invalid timestamp bits", text: strings.TrimSuffix(header, "\n") + " timestamp-bits=x\n7\n",
+			want: ErrInvalidTextDump},
+		{name: "timestamp bits too large", text: strings.TrimSuffix(header, "\n") + " timestamp-bits=63\n7\n",
+			want: ErrTimestampBitsTooLarge},
+		{name: "invalid layout", text: strings.Replace(header, "machine-id-bits=10", "machine-id-bits=22", 1), want: ErrMachineBitsTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReadIDsText(strings.NewReader(tt.text)); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package snowflake
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidTimeUnit is returned when the time unit is shorter than a microsecond, or neither divides a second nor
+	// is a whole number of seconds
+	ErrInvalidTimeUnit = errors.New("time unit must be a microsecond or longer and divide a second or be whole seconds")
+)
+
+// WithTimeUnit sets the unit of the timestamp, which is a millisecond by default
+// A shorter unit such as time.Microsecond gives every unit its own sequence, which raises the number of IDs per
+// second, but it shortens the lifetime of the timestamp bits by the same factor: 42 bits of microseconds last 51
+// days instead of 139 years, see EpochExhaustionTime. Combine it with a recent epoch and WithLayout for more
+// timestamp bits. A longer unit, such as the 10ms of Sonyflake, extends the lifetime instead
+// The time function returns the time in units since the Unix epoch, and everything the generator describes in
+// milliseconds, such as the sequence per millisecond, drift and timestamp quantization, is in units. Time,
+// LastTimestamp and the other accessors convert the timestamp back to the wall clock, DecodeID returns it in units
+// Returns ErrInvalidTimeUnit from NewGenerator if the unit is invalid, and ErrTimestampOverflow if the current time
+// no longer fits in the timestamp bits with the unit
+func WithTimeUnit(unit time.Duration) Option {
+	return func(generator *Generator) {
+		generator.unit = unit
+	}
+}
+
+// WithMicrosecondResolution sets the unit of the timestamp to a microsecond, it is equivalent to
+// WithTimeUnit(time.Microsecond)
+func WithMicrosecondResolution() Option {
+	return WithTimeUnit(time.Microsecond)
+}
+
+// validTimeUnit reports whether the unit is a microsecond or longer and divides a second or is whole seconds
+func validTimeUnit(unit time.Duration) bool {
+	return unit >= time.Microsecond && (time.Second%unit == 0 || unit%time.Second == 0)
+}
+
+// ticks returns the number of time units between the Unix epoch and t
+func (g *Generator) ticks(t time.Time) int64 {
+	if g.unit > time.Second {
+		return t.Unix() / int64(g.unit/time.Second)
+	}
+	return t.Unix()*int64(time.Second/g.unit) + int64(t.Nanosecond())/int64(g.unit)
+}
+
+// timeOf returns the time of the given number of time units since the Unix epoch
+func (g *Generator) timeOf(ticks int64) time.Time {
+	if g.unit > time.Second {
+		return time.Unix(ticks*int64(g.unit/time.Second), 0)
+	}
+	perSecond := int64(time.Second / g.unit)
+	return time.Unix(ticks/perSecond, ticks%perSecond*int64(g.unit))
+}
+
+// units returns the number of whole time units in d
+func (g *Generator) units(d time.Duration) uint64 {
+	return uint64(d / g.unit)
+}
+
+// millis returns the Unix time in milliseconds of the given number of time units since the Unix epoch
+func (g *Generator) millis(ticks uint64) uint64 {
+	if g.unit == time.Millisecond {
+		return ticks
+	}
+	return uint64(g.timeOf(int64(ticks)).UnixMilli())
+}
+
+// sleepUntilNext sleeps until the next whole time unit of the wall clock
+func sleepUntilNext(unit time.Duration) {
+	nano := time.Duration(time.Now().UnixNano())
+	time.Sleep(unit - nano%unit + 1*time.Nanosecond)
+}
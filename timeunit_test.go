@@ -0,0 +1,169 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithTimeUnit tests that the timestamp counts time units and that the accessors convert it back to the wall clock
+func TestWithTimeUnit(t *testing.T) {
+	epoch := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		unit       time.Duration
+		now        time.Time
+		timestamp  uint64
+		exhaustion time.Time
+	}{
+		{
+			name:       "microsecond",
+			unit:       time.Microsecond,
+			now:        epoch.Add(90*time.Minute + 1234*time.Microsecond),
+			timestamp:  uint64((90*time.Minute + 1234*time.Microsecond) / time.Microsecond),
+			exhaustion: epoch.Add(1 << 42 * time.Microsecond),
+		},
+		{
+			name:       "ten milliseconds",
+			unit:       10 * time.Millisecond,
+			now:        epoch.Add(90*time.Minute + 120*time.Millisecond),
+			timestamp:  uint64((90*time.Minute + 120*time.Millisecond) / (10 * time.Millisecond)),
+			exhaustion: time.UnixMilli(epoch.UnixMilli() + 10<<42),
+		},
+		{
+			name:       "two seconds",
+			unit:       2 * time.Second,
+			now:        epoch.Add(90 * time.Minute),
+			timestamp:  uint64(90 * time.Minute / (2 * time.Second)),
+			exhaustion: time.Unix(epoch.Unix()+2<<42, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(tt.now)
+			generator, err := NewGenerator(378, WithEpoch(epoch), WithTimeUnit(tt.unit), WithClockInterface(clock))
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			id, err := generator.NextID()
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			verifyRoundTrip(t, generator, id, tt.timestamp, 0)
+			if got := generator.Time(id); !got.Equal(tt.now) {
+				t.Errorf("expected %v, got %v", tt.now, got)
+			}
+			if got := generator.LastTimestamp(); !got.Equal(tt.now) {
+				t.Errorf("expected %v, got %v", tt.now, got)
+			}
+			if got := generator.Components(id).Time; !got.Equal(tt.now) {
+				t.Errorf("expected %v, got %v", tt.now, got)
+			}
+			if got := generator.Epoch(); !got.Equal(epoch) {
+				t.Errorf("expected %v, got %v", epoch, got)
+			}
+			if got := generator.EpochExhaustionTime(); !got.Equal(tt.exhaustion) {
+				t.Errorf("expected %v, got %v", tt.exhaustion, got)
+			}
+			if got := generator.Describe(); !strings.Contains(got, "unit="+tt.unit.String()) {
+				t.Errorf("expected the unit %v in %v", tt.unit, got)
+			}
+		})
+	}
+}
+
+// TestWithTimeUnit_Invalid tests that NewGenerator rejects invalid time units and a timestamp that no longer fits
+func TestWithTimeUnit_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want error
+	}{
+		{name: "zero", opts: []Option{WithTimeUnit(0)}, want: ErrInvalidTimeUnit},
+		{name: "nanoseconds", opts: []Option{WithTimeUnit(500 * time.Nanosecond)}, want: ErrInvalidTimeUnit},
+		{name: "not dividing a second", opts: []Option{WithTimeUnit(3 * time.Millisecond)}, want: ErrInvalidTimeUnit},
+		{name: "not whole seconds", opts: []Option{WithTimeUnit(1500 * time.Millisecond)}, want: ErrInvalidTimeUnit},
+		{
+			name: "quantization not a whole number of units",
+			opts: []Option{WithTimeUnit(10 * time.Millisecond), WithTimestampQuantization(15 * time.Millisecond)},
+			want: ErrInvalidQuantization,
+		},
+		{
+			name: "exhausted",
+			opts: []Option{WithEpoch(time.Now().Add(-60 * 24 * time.Hour)), WithMicrosecondResolution()},
+			want: ErrTimestampOverflow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGenerator(1, tt.opts...); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestWithMicrosecondResolution tests that the default clock and sleep use microseconds, so a sequence of one ID per
+// microsecond blocks for microseconds instead of milliseconds
+func TestWithMicrosecondResolution(t *testing.T) {
+	generator, err := NewGenerator(1, WithEpoch(time.Now().Add(-time.Hour)), WithMicrosecondResolution(),
+		WithMachineIDBits(21))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	start := time.Now()
+	ids := make([]ID, 100)
+	for i := range ids {
+		if ids[i], err = generator.BlockingNextID(context.Background()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if err = CheckMonotonicUnique(ids); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	for _, id := range ids {
+		if got := generator.Time(id); got.Before(start.Truncate(time.Microsecond)) || got.After(time.Now()) {
+			t.Errorf("expected a time between %v and now, got %v", start, got)
+			return
+		}
+	}
+}
+
+// TestWithMicrosecondResolution_ClockMovedBackwards tests that the rollback in strict mode is reported as a duration
+// instead of a number of units
+func TestWithMicrosecondResolution_ClockMovedBackwards(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "sequence"},
+		{name: "strategy", opts: []Option{WithSequenceStrategy(NewIncrementSequenceStrategy(12))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Unix(1700000000, 0)
+			clock := NewManualClock(start)
+			generator, err := NewGenerator(1, append([]Option{WithEpoch(start.Add(-time.Hour)),
+				WithMicrosecondResolution(), WithStrict(), WithClockInterface(clock)}, tt.opts...)...)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if _, err = generator.NextID(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			clock.Set(start.Add(-5 * time.Millisecond))
+			_, err = generator.NextID()
+			if want := "clock moved backwards: 5ms behind the last generated ID"; err == nil || err.Error() != want {
+				t.Errorf("expected %v, got %v", want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package snowflake
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer records ID generation events on the trace span found in a context
+// Implement it with a thin adapter around your tracing library, e.g. trace.SpanFromContext(ctx).AddEvent for
+// OpenTelemetry, so this module stays dependency free
+type Tracer interface {
+	// SequenceExhausted is called when the sequence is exhausted and the generator has to block
+	SequenceExhausted(ctx context.Context)
+	// Blocked is called with the duration the generator blocked before it could generate an ID
+	Blocked(ctx context.Context, duration time.Duration)
+}
+
+// WithTracer sets the tracer used by NextIDCtx
+func WithTracer(tracer Tracer) Option {
+	return func(generator *Generator) {
+		generator.tracer = tracer
+	}
+}
+
+// NextIDCtx generates a new snowflake ID like BlockingNextID and records sequence exhaustion and the duration it
+// blocked on the configured Tracer, also the wait for the rate limit
+// Without a tracer this is equivalent to BlockingNextID
+func (g *Generator) NextIDCtx(ctx context.Context) (ID, error) {
+	return g.blockingNextID(canceledBy(ctx), g.tracer, ctx)
+}
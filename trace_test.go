@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type testTracer struct {
+	exhausted int
+	blocked   []time.Duration
+}
+
+func (t *testTracer) SequenceExhausted(context.Context) {
+	t.exhausted++
+}
+
+func (t *testTracer) Blocked(_ context.Context, duration time.Duration) {
+	t.blocked = append(t.blocked, duration)
+}
+
+// TestGenerator_NextIDCtx tests that NextIDCtx records sequence exhaustion and blocking on the tracer
+func TestGenerator_NextIDCtx(t *testing.T) {
+	tracer := &testTracer{}
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithTracer(tracer))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485447
+	})
+	generator.sleepFunc = func() {
+		time.Sleep(time.Millisecond)
+		generator.SetTimeFunc(func() uint64 {
+			return 367597485448
+		})
+	}
+
+	var id ID
+	for i := uint64(0); i <= generator.sequenceMask+1; i++ {
+		id, err = generator.NextIDCtx(context.Background())
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+
+	if id != 1541815603606036480 {
+		t.Errorf("expected 1541815603606036480, got %v", id)
+	}
+	if tracer.exhausted != 1 {
+		t.Errorf("expected 1 sequence exhaustion, got %v", tracer.exhausted)
+	}
+	if len(tracer.blocked) != 1 || tracer.blocked[0] < time.Millisecond {
+		t.Errorf("expected 1 blocking duration of at least 1ms, got %v", tracer.blocked)
+	}
+}
+
+// TestGenerator_NextIDCtx_WithoutTracer tests that NextIDCtx works without a tracer
+func TestGenerator_NextIDCtx_WithoutTracer(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+
+	id, err := generator.NextIDCtx(context.Background())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if id != 1541815603606036480 {
+		t.Errorf("expected 1541815603606036480, got %v", id)
+	}
+}
+
+// TestGenerator_NextIDCtx_RateLimit tests that NextIDCtx with a tracer paces itself to the rate limit like
+// BlockingNextID and records the wait on the tracer
+func TestGenerator_NextIDCtx_RateLimit(t *testing.T) {
+	tracer := &testTracer{}
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithRateLimit(100), WithTracer(tracer))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	start := uint64(367597485448)
+	now := start
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+	generator.sleepFunc = func() {
+		now++
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err = generator.NextIDCtx(context.Background()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if now-start != 40 {
+		t.Errorf("expected 40ms to have elapsed, got %vms", now-start)
+	}
+	if tracer.exhausted != 0 {
+		t.Errorf("expected no sequence exhaustion, got %v", tracer.exhausted)
+	}
+	if len(tracer.blocked) != 4 {
+		t.Errorf("expected 4 blocking durations, got %v", tracer.blocked)
+	}
+}
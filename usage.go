@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// usageHistogram counts the number of milliseconds per number of generated IDs, in power of two buckets
+type usageHistogram struct {
+	buckets [65]atomic.Uint64
+	// start is the state of the first ID of the current millisecond
+	start atomic.Uint64
+}
+
+// record records a millisecond in which count IDs were generated
+func (h *usageHistogram) record(count uint64) {
+	h.buckets[bits.Len64(count-1)].Add(1)
+}
+
+// advance records the millisecond that ended with the state last, now that first starts a later millisecond
+// The IDs of a millisecond are counted from its first ID, which does not have to be sequence zero with a random or
+// initial sequence start. A millisecond whose first ID is not known, because the generator moved on before it was
+// stored, is not recorded
+func (h *usageHistogram) advance(last, first, stateShift, sequenceMask uint64) {
+	for {
+		start := h.start.Load()
+		if start != 0 && start>>stateShift >= first>>stateShift {
+			// A later millisecond has already started
+			return
+		}
+		if h.start.CompareAndSwap(start, first) {
+			if last != 0 && start != 0 && start>>stateShift == last>>stateShift {
+				h.record(last&sequenceMask - start&sequenceMask + 1)
+			}
+			return
+		}
+	}
+}
+
+// WithUsageHistogram enables recording how many IDs are generated per millisecond
+// Use UsageHistogram to read the histogram
+func WithUsageHistogram() Option {
+	return func(generator *Generator) {
+		generator.usage = &usageHistogram{}
+	}
+}
+
+// UsageHistogram returns the number of milliseconds per number of generated IDs
+// The keys are power of two buckets, a key holds the milliseconds with more than half the key and at most key IDs
+// A millisecond is recorded when the generator moves on to the next millisecond, so the current one is not included
+// Returns nil if WithUsageHistogram is not enabled
+func (g *Generator) UsageHistogram() map[int]uint64 {
+	if g.usage == nil {
+		return nil
+	}
+	histogram := make(map[int]uint64)
+	for i := range g.usage.buckets {
+		if n := g.usage.buckets[i].Load(); n > 0 {
+			histogram[1<<i] = n
+		}
+	}
+	return histogram
+}
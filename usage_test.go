@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestGenerator_UsageHistogram tests that UsageHistogram records the number of IDs per millisecond
+func TestGenerator_UsageHistogram(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithUsageHistogram())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	for _, count := range []int{1, 3, 4, 4096, 1, 7} {
+		for i := 0; i < count; i++ {
+			if _, err = generator.NextID(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+		}
+		now++
+	}
+
+	want := map[int]uint64{1: 2, 4: 2, 4096: 1}
+	if got := generator.UsageHistogram(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	want = map[int]uint64{1: 2, 4: 2, 8: 1, 4096: 1}
+	if got := generator.UsageHistogram(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestGenerator_UsageHistogram_SequenceStart tests that UsageHistogram counts the IDs of a millisecond from its first
+// ID with an initial and a random sequence start
+func TestGenerator_UsageHistogram_SequenceStart(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithUsageHistogram(), WithInitialSequence(100),
+		WithRandomSequenceStart(rand.New(rand.NewSource(1))))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	for _, count := range []int{1, 3, 4, 7} {
+		for i := 0; i < count; i++ {
+			if _, err = generator.NextID(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+		}
+		now++
+	}
+	if _, err = generator.NextID(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	want := map[int]uint64{1: 1, 4: 2, 8: 1}
+	if got := generator.UsageHistogram(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestGenerator_UsageHistogram_Disabled tests that UsageHistogram returns nil without WithUsageHistogram
+func TestGenerator_UsageHistogram_Disabled(t *testing.T) {
+	generator, err := NewGenerator(378)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if got := generator.UsageHistogram(); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrMachineIDMismatch is returned when an ID has a different machine ID than the generator
+	ErrMachineIDMismatch = errors.New("machine ID mismatch")
+	// ErrVersionMismatch is returned when an ID has a different version than the generator
+	ErrVersionMismatch = errors.New("version mismatch")
+	// ErrTimeInFuture is returned when the timestamp of an ID is later than the generator could have generated it
+	ErrTimeInFuture = errors.New("time is in the future")
+	// ErrTimeBeforeRange is returned when the time of an ID is before the allowed range
+	ErrTimeBeforeRange = errors.New("time is before the allowed range")
+	// ErrTimeAfterRange is returned when the time of an ID is after the allowed range
+	ErrTimeAfterRange = errors.New("time is after the allowed range")
+	// ErrDuplicateID is returned when a batch of IDs contains the same ID twice in a row
+	ErrDuplicateID = errors.New("duplicate ID")
+	// ErrNotIncreasing is returned when an ID of a batch is smaller than the ID before it
+	ErrNotIncreasing = errors.New("IDs are not increasing")
+)
+
+// BelongsTo reports whether the machine ID bits of the ID match the machine ID of the generator
+// It only checks the machine ID, use Validate to also check the version and the timestamp
+func (g *Generator) BelongsTo(id ID) bool {
+	return uint64(id)>>g.machineIDShift&g.machineIDMask == g.machineID.Load()
+}
+
+// Validate returns an error if the ID could not have been generated by this generator
+// It checks the machine ID, the version when the version bit is reserved and that the timestamp is not later than
+// now plus the allowed drift or the maximum future offset of NextIDAt, whichever is later
+// Returns ErrMachineIDMismatch, ErrVersionMismatch or ErrTimeInFuture wrapped with the offending values
+func (g *Generator) Validate(id ID) error {
+	decoded := g.DecodeID(id)
+	if machineID := g.machineID.Load(); decoded.MachineID != machineID {
+		return fmt.Errorf("%w: got %d, want %d", ErrMachineIDMismatch, decoded.MachineID, machineID)
+	}
+	if g.layout.VersionBit && decoded.Version != g.version {
+		return fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, decoded.Version, g.version)
+	}
+	ahead := g.driftWindow()
+	if offset := g.units(g.maxFutureOffset); offset > ahead {
+		ahead = offset
+	}
+	now := int64(g.now()) - g.epoch
+	if limit := now + int64(ahead); int64(decoded.Timestamp) > limit {
+		return fmt.Errorf("%w: timestamp %d is after %d", ErrTimeInFuture, decoded.Timestamp, limit)
+	}
+	return nil
+}
+
+// ValidateID returns an error if the time of the ID is not within [notBefore, notAfter], without a generator
+// The timestamp is decoded with the layout and added to the epoch, which the layout does not hold
+// Returns ErrTimeBeforeRange or ErrTimeAfterRange wrapped with the offending values
+func ValidateID(id ID, layout Layout, epoch, notBefore, notAfter time.Time) error {
+	t := time.UnixMilli(epoch.UnixMilli() + int64(DecodeID(id, layout).Timestamp))
+	if t.Before(notBefore) {
+		return fmt.Errorf("%w: %v is before %v", ErrTimeBeforeRange, t.UTC(), notBefore.UTC())
+	}
+	if t.After(notAfter) {
+		return fmt.Errorf("%w: %v is after %v", ErrTimeAfterRange, t.UTC(), notAfter.UTC())
+	}
+	return nil
+}
+
+// CheckMonotonicUnique returns an error if the IDs are not strictly increasing, which also means they are unique
+// It makes a single pass over the IDs in O(n) without sorting or allocating, so the IDs must be in the order they
+// were generated or exported, a duplicate is only detected as such when it directly follows the same ID and is
+// reported as ErrNotIncreasing otherwise
+// Returns ErrDuplicateID or ErrNotIncreasing wrapped with the offending index and values
+func CheckMonotonicUnique(ids []ID) error {
+	for i := 1; i < len(ids); i++ {
+		switch {
+		case ids[i] == ids[i-1]:
+			return fmt.Errorf("%w: index %d is %d, the same as index %d", ErrDuplicateID, i, uint64(ids[i]), i-1)
+		case ids[i] < ids[i-1]:
+			return fmt.Errorf("%w: index %d is %d, which is smaller than %d at index %d", ErrNotIncreasing, i,
+				uint64(ids[i]), uint64(ids[i-1]), i-1)
+		}
+	}
+	return nil
+}
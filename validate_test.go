@@ -0,0 +1,190 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestGenerator_Validate tests the Validate method of the Generator
+func TestGenerator_Validate(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithVersionBit(1), WithDriftNoWait(10*time.Millisecond))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	id, err := generator.NextID()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+
+	tests := []struct {
+		name string
+		id   ID
+		want error
+	}{
+		{name: "generated ID", id: id},
+		{name: "ID within drift", id: id + 10<<timeShift},
+		{name: "ID after drift", id: id + 11<<timeShift, want: ErrTimeInFuture},
+		{name: "other machine ID", id: id ^ 1<<12, want: ErrMachineIDMismatch},
+		{name: "other version", id: id ^ 1<<63, want: ErrVersionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := generator.Validate(tt.id); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestGenerator_Validate_NextIDAt tests that Validate accepts the IDs NextIDAt generates up to the maximum future
+// offset
+func TestGenerator_Validate_NextIDAt(t *testing.T) {
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithMaxFutureOffset(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 367597485448
+	})
+	id, err := generator.NextIDAt(time.UnixMilli(367597486448))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	if err = generator.Validate(id); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err = generator.Validate(id + 1<<timeShift); !errors.Is(err, ErrTimeInFuture) {
+		t.Errorf("expected %v, got %v", ErrTimeInFuture, err)
+	}
+}
+
+// TestGenerator_BelongsTo tests that BelongsTo tells the IDs of generators with different machine IDs apart
+func TestGenerator_BelongsTo(t *testing.T) {
+	a, err := NewGenerator(378, WithDriftNoWait(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	b, err := NewGenerator(379, WithDriftNoWait(time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	for i := 0; i < 100; i++ {
+		generator, other := a, b
+		if i%2 == 1 {
+			generator, other = b, a
+		}
+		id, err := generator.NextID()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		if !generator.BelongsTo(id) {
+			t.Errorf("expected %v to belong to machine ID %v", generator.DecodeID(id), generator.MachineID())
+		}
+		if other.BelongsTo(id) {
+			t.Errorf("expected %v not to belong to machine ID %v", generator.DecodeID(id), other.MachineID())
+		}
+	}
+}
+
+// TestValidateID tests the ValidateID function with the Twitter test vector
+func TestValidateID(t *testing.T) {
+	epoch := time.UnixMilli(1288834974657)
+	// The first Tweet was created at 1656432460105
+	id := ID(1541815603606036480)
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		notAfter  time.Time
+		wantErr   error
+	}{
+		{name: "within range", notBefore: time.UnixMilli(1656432460000), notAfter: time.UnixMilli(1656432461000)},
+		{name: "at the bounds", notBefore: time.UnixMilli(1656432460105), notAfter: time.UnixMilli(1656432460105)},
+		{
+			name:      "before range",
+			notBefore: time.UnixMilli(1656432460106),
+			notAfter:  time.UnixMilli(1656432461000),
+			wantErr:   ErrTimeBeforeRange,
+		},
+		{
+			name:      "after range",
+			notBefore: time.UnixMilli(1656432460000),
+			notAfter:  time.UnixMilli(1656432460104),
+			wantErr:   ErrTimeAfterRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateID(id, DefaultLayout(), epoch, tt.notBefore, tt.notAfter); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestCheckMonotonicUnique tests that CheckMonotonicUnique reports duplicates and decreasing IDs with their index
+func TestCheckMonotonicUnique(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []ID
+		expected error
+		message  string
+	}{
+		{name: "empty"},
+		{name: "single", ids: []ID{5}},
+		{name: "increasing", ids: []ID{1, 2, 10}},
+		{
+			name:     "duplicate",
+			ids:      []ID{1, 2, 2},
+			expected: ErrDuplicateID,
+			message:  "duplicate ID: index 2 is 2, the same as index 1",
+		},
+		{
+			name:     "decreasing",
+			ids:      []ID{1, 5, 3},
+			expected: ErrNotIncreasing,
+			message:  "IDs are not increasing: index 2 is 3, which is smaller than 5 at index 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckMonotonicUnique(tt.ids)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+				return
+			}
+			if err != nil && err.Error() != tt.message {
+				t.Errorf("expected %v, got %v", tt.message, err)
+			}
+		})
+	}
+}
+
+// TestCheckMonotonicUnique_Generator tests that the IDs of a generator pass the check
+func TestCheckMonotonicUnique_Generator(t *testing.T) {
+	generator, err := NewGenerator(378, WithDriftNoWait(time.Hour))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ids := make([]ID, 10000)
+	for i := range ids {
+		if ids[i], err = generator.NextID(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if err := CheckMonotonicUnique(ids); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"runtime"
+	"time"
+)
+
+// WaitStrategy waits for the clock when the sequence is exhausted, before the generator tries again
+type WaitStrategy interface {
+	// Wait blocks until the next time unit, now returns the time in units since the Unix epoch
+	Wait(unit time.Duration, now TimeFunc)
+}
+
+// SleepWait sleeps until the next time unit of the wall clock, which is what the generator does by default
+// It yields the CPU but the scheduler can wake it up well after the clock moved on
+type SleepWait struct{}
+
+// Wait sleeps until the next time unit of the wall clock
+func (SleepWait) Wait(unit time.Duration, _ TimeFunc) {
+	sleepUntilNext(unit)
+}
+
+// SpinWait yields the processor with runtime.Gosched until the time function moves on, which trades CPU time for a
+// lower latency than SleepWait
+// It only ends when the time function advances on its own, so do not use it with a ManualClock
+type SpinWait struct{}
+
+// Wait yields the processor until now returns a later time than when Wait was called
+func (SpinWait) Wait(_ time.Duration, now TimeFunc) {
+	for start := now(); now() == start; {
+		runtime.Gosched()
+	}
+}
+
+// WithWaitStrategy sets how the generator waits for the clock when the sequence is exhausted, the default is SleepWait
+// It replaces the sleep function, including the sleep of WithClockInterface and WithExactSleep when it is set after
+// them
+func WithWaitStrategy(s WaitStrategy) Option {
+	return func(generator *Generator) {
+		generator.sleepFunc = func() {
+			s.Wait(generator.unit, generator.now)
+		}
+	}
+}
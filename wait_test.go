@@ -0,0 +1,78 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type countingWait struct {
+	calls int
+	clock *ManualClock
+}
+
+func (w *countingWait) Wait(unit time.Duration, _ TimeFunc) {
+	w.calls++
+	w.clock.Advance(unit)
+}
+
+// TestWithWaitStrategy tests that the generator waits with the wait strategy when the sequence is exhausted
+func TestWithWaitStrategy(t *testing.T) {
+	clock := NewManualClock(time.UnixMilli(367597485448))
+	wait := &countingWait{clock: clock}
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithClockInterface(clock), WithWaitStrategy(wait))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	var id ID
+	for i := uint64(0); i <= generator.sequenceMask+1; i++ {
+		if id, err = generator.BlockingNextID(context.Background()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	verifyRoundTrip(t, generator, id, 367597485449, 0)
+	if wait.calls != 1 {
+		t.Errorf("expected 1 wait, got %v", wait.calls)
+	}
+}
+
+// TestWaitStrategies tests that SleepWait and SpinWait return once the clock has moved on to the next millisecond
+func TestWaitStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy WaitStrategy
+	}{
+		{name: "sleep", strategy: SleepWait{}},
+		{name: "spin", strategy: SpinWait{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := defaultTimeFunc()
+			tt.strategy.Wait(time.Millisecond, defaultTimeFunc)
+			if now := defaultTimeFunc(); now <= start {
+				t.Errorf("expected a time after %v, got %v", start, now)
+			}
+		})
+	}
+}
+
+// TestWithWaitStrategy_Spin tests that BlockingNextID spins to the next millisecond with SpinWait
+func TestWithWaitStrategy_Spin(t *testing.T) {
+	generator, err := NewGenerator(378, WithMachineIDBits(20), WithWaitStrategy(SpinWait{}))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	ids := make([]ID, 20)
+	for i := range ids {
+		if ids[i], err = generator.BlockingNextID(context.Background()); err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+	}
+	if err = CheckMonotonicUnique(ids); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
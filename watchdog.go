@@ -0,0 +1,50 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrClockStalled is returned when the time function has not advanced while the reference clock has
+	ErrClockStalled = errors.New("clock stalled")
+)
+
+// clockWatchdog detects a time function that stops advancing by comparing it with a reference clock
+type clockWatchdog struct {
+	clock     func() time.Time
+	stall     time.Duration
+	last      atomic.Uint64
+	changedAt atomic.Int64
+}
+
+// observe records a reading of the time function, in milliseconds since the epoch
+// Returns ErrClockStalled if the reading is the same as the reading the reference clock stall ago
+func (w *clockWatchdog) observe(now uint64) error {
+	reference := w.clock().UnixNano()
+	last := w.last.Load()
+	if now != last {
+		if w.last.CompareAndSwap(last, now) {
+			w.changedAt.Store(reference)
+		}
+		return nil
+	}
+	if time.Duration(reference-w.changedAt.Load()) >= w.stall {
+		return ErrClockStalled
+	}
+	return nil
+}
+
+// WithClockWatchdog returns ErrClockStalled from the generator when the time function returns the same millisecond
+// for stall or longer according to the reference clock, for example the wall clock of a frozen virtual machine
+// A stalled clock exhausts the sequence, after which BlockingNextID would block forever, the watchdog turns the hang
+// into an error. The reference clock is usually time.Now, stall should be well above the longest expected pause
+func WithClockWatchdog(clock func() time.Time, stall time.Duration) Option {
+	return func(generator *Generator) {
+		generator.watchdog = &clockWatchdog{clock: clock, stall: stall}
+		// No reading of the time function matches, so the first reading starts the watch
+		generator.watchdog.last.Store(math.MaxUint64)
+	}
+}
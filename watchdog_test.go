@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestWithClockWatchdog tests that a time function that stops advancing is detected with the reference clock
+func TestWithClockWatchdog(t *testing.T) {
+	reference := time.Unix(1700000000, 0)
+	generator, err := NewGenerator(378, WithEpoch(time.UnixMilli(0)), WithClockWatchdog(func() time.Time {
+		return reference
+	}, time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	now := uint64(367597485448)
+	generator.SetTimeFunc(func() uint64 {
+		return now
+	})
+
+	tests := []struct {
+		name      string
+		advance   uint64
+		reference time.Duration
+		want      error
+	}{
+		{name: "first reading", want: nil},
+		{name: "stalled shorter than the limit", reference: 999 * time.Millisecond, want: nil},
+		{name: "stalled", reference: time.Millisecond, want: ErrClockStalled},
+		{name: "still stalled", reference: time.Hour, want: ErrClockStalled},
+		{name: "advanced", advance: 1, want: nil},
+		{name: "stalled again shorter than the limit", reference: 500 * time.Millisecond, want: nil},
+	}
+	for _, tt := range tests {
+		now += tt.advance
+		reference = reference.Add(tt.reference)
+		if _, err := generator.NextID(); !errors.Is(err, tt.want) {
+			t.Errorf("%v: expected %v, got %v", tt.name, tt.want, err)
+			return
+		}
+	}
+}
+
+// TestWithClockWatchdog_BlockingNextID tests that BlockingNextID returns instead of blocking on a stalled clock
+func TestWithClockWatchdog_BlockingNextID(t *testing.T) {
+	reference := time.Unix(1700000000, 0)
+	generator, err := NewGenerator(378, WithMachineIDBits(21), WithClockWatchdog(func() time.Time {
+		return reference
+	}, time.Second))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+		return
+	}
+	generator.SetTimeFunc(func() uint64 {
+		return 1800000000000
+	})
+	generator.sleepFunc = func() {
+		reference = reference.Add(time.Millisecond)
+	}
+
+	for {
+		if _, err = generator.BlockingNextID(context.TODO()); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, ErrClockStalled) {
+		t.Errorf("expected ErrClockStalled, got %v", err)
+	}
+}